@@ -0,0 +1,509 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiTimeout = 30 * time.Second
+
+// maxAPIPages bounds --paginate so a server that keeps returning rel="next"
+// cannot keep gitcrn looping forever.
+const maxAPIPages = 100
+
+// giteaClient sends authenticated requests to server_url/api/v1. Paths are
+// relative to /api/v1, e.g. "repos/vltc/kapri/issues".
+type giteaClient struct {
+	serverURL string
+	token     string
+}
+
+func newGiteaClient() (*giteaClient, error) {
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return nil, err
+	}
+	token := resolveToken(cfg)
+	if token == "" {
+		return nil, errMissingToken
+	}
+	return &giteaClient{serverURL: resolveServerURL(cfg), token: token}, nil
+}
+
+func (c *giteaClient) endpoint(apiPath string) string {
+	p := strings.TrimSpace(apiPath)
+	if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+		return p
+	}
+	p = strings.TrimPrefix(p, "/")
+	p = strings.TrimPrefix(p, "api/v1/")
+	return c.serverURL + "/api/v1/" + p
+}
+
+// isServerURL reports whether u points at the configured server. Absolute
+// URLs (api arguments, Link headers, asset links) can name any host, and the
+// token must only go to server_url.
+func (c *giteaClient) isServerURL(u *url.URL) bool {
+	base, err := url.Parse(c.serverURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
+
+func (c *giteaClient) newRequest(ctx context.Context, method, apiPath string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint(apiPath), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.isServerURL(req.URL) {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", appName)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// doJSON sends payload (if any) as JSON and decodes a 2xx response into out
+// (if non-nil). Non-2xx responses become errors carrying the Gitea message.
+func (c *giteaClient) doJSON(method, apiPath string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, method, apiPath, body)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &giteaAPIError{Status: resp.StatusCode, Message: giteaErrorMessage(resp.Body)}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type giteaAPIError struct {
+	Status  int
+	Message string
+}
+
+func (e *giteaAPIError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.Status, e.Message)
+}
+
+func isNotFound(err error) bool {
+	var apiErr *giteaAPIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+type stringListFlag []string
+
+func (s *stringListFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringListFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func runAPI(args []string) error {
+	fs := newFlagSet("api")
+
+	var rawFields, typedFields, headers stringListFlag
+	fs.Var(&rawFields, "f", "Поље key=value (string)")
+	fs.Var(&typedFields, "F", "Поље key=value (true/false/null/број се претварају)")
+	fs.Var(&headers, "H", "Додатни HTTP header \"Key: Value\"")
+	input := fs.String("input", "", "JSON тело из фајла или - за stdin")
+	paginate := fs.Bool("paginate", false, "Прати странице и споји резултате")
+	filter := fs.String("q", "", "Филтер путања (нпр .[].full_name)")
+	silent := fs.Bool("silent", false, "Не исписуј одговор")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printAPIUsage(os.Stdout)
+			return nil
+		}
+		printAPIUsage(os.Stderr)
+		return err
+	}
+
+	method := http.MethodGet
+	var apiPath string
+	switch len(positional) {
+	case 1:
+		apiPath = positional[0]
+	case 2:
+		method = strings.ToUpper(strings.TrimSpace(positional[0]))
+		apiPath = positional[1]
+	default:
+		printAPIUsage(os.Stderr)
+		return errors.New("api тражи <method> <path> или <path>")
+	}
+
+	client, err := newGiteaClient()
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(apiPath, "{owner}") || strings.Contains(apiPath, "{repo}") {
+		owner, repoName, err := inferOwnerRepo(client.serverURL)
+		if err != nil {
+			return err
+		}
+		apiPath = strings.NewReplacer("{owner}", owner, "{repo}", repoName).Replace(apiPath)
+	}
+
+	fields, err := parseAPIFields(rawFields, typedFields)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if *input != "" {
+		if len(fields) > 0 {
+			return errors.New("--input се не може комбиновати са -f/-F")
+		}
+		if *input == "-" {
+			body, err = io.ReadAll(os.Stdin)
+		} else {
+			body, err = os.ReadFile(*input)
+		}
+		if err != nil {
+			return fmt.Errorf("читање тела захтева: %w", err)
+		}
+	} else if len(fields) > 0 {
+		if method == http.MethodGet || method == http.MethodDelete {
+			apiPath = appendQuery(apiPath, fields)
+		} else {
+			body, err = json.Marshal(fields)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	extraHeaders := http.Header{}
+	for _, h := range headers {
+		k, v, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("невалидан header: %s (очекује се \"Key: Value\")", h)
+		}
+		extraHeaders.Add(strings.TrimSpace(k), strings.TrimSpace(v))
+	}
+
+	result, err := client.raw(method, apiPath, body, extraHeaders, *paginate)
+	if err != nil {
+		return err
+	}
+	if *silent || len(bytes.TrimSpace(result)) == 0 {
+		return nil
+	}
+
+	if *filter != "" {
+		var doc any
+		if err := json.Unmarshal(result, &doc); err != nil {
+			return fmt.Errorf("одговор није JSON, филтер није применљив: %w", err)
+		}
+		values, err := applyJSONPath(doc, *filter)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if err := printJSONValue(os.Stdout, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, result, "", "  "); err != nil {
+		_, err = os.Stdout.Write(result)
+		return err
+	}
+	pretty.WriteByte('\n')
+	_, err = os.Stdout.Write(pretty.Bytes())
+	return err
+}
+
+// raw performs a request and returns the response body. With paginate, it
+// follows rel="next" Link headers and merges JSON arrays into one array.
+func (c *giteaClient) raw(method, apiPath string, body []byte, headers http.Header, paginate bool) ([]byte, error) {
+	next := c.endpoint(apiPath)
+	var merged []json.RawMessage
+	pages := 0
+
+	for next != "" {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+		req, err := c.newRequest(ctx, method, next, reader)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		for k, vals := range headers {
+			for _, v := range vals {
+				req.Header.Add(k, v)
+			}
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		cancel()
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, &giteaAPIError{Status: resp.StatusCode, Message: giteaErrorMessage(bytes.NewReader(data))}
+		}
+		if !paginate {
+			return data, nil
+		}
+
+		var page []json.RawMessage
+		if err := json.Unmarshal(data, &page); err != nil {
+			if pages == 0 {
+				return data, nil
+			}
+			return nil, errors.New("--paginate: одговор странице није JSON низ")
+		}
+		merged = append(merged, page...)
+		pages++
+
+		next = nextPageURL(resp.Header.Get("Link"))
+		if len(page) == 0 {
+			next = ""
+		}
+		if next != "" && pages >= maxAPIPages {
+			return nil, fmt.Errorf("--paginate: више од %d страница, прекидам", maxAPIPages)
+		}
+	}
+
+	if merged == nil {
+		merged = []json.RawMessage{}
+	}
+	return json.Marshal(merged)
+}
+
+var linkNextRe = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+func nextPageURL(link string) string {
+	m := linkNextRe.FindStringSubmatch(link)
+	if len(m) != 2 {
+		return ""
+	}
+	return m[1]
+}
+
+func parseAPIFields(raw, typed []string) (map[string]any, error) {
+	fields := map[string]any{}
+	for _, f := range raw {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("невалидно поље: %s (очекује се key=value)", f)
+		}
+		fields[strings.TrimSpace(k)] = v
+	}
+	for _, f := range typed {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("невалидно поље: %s (очекује се key=value)", f)
+		}
+		fields[strings.TrimSpace(k)] = typedFieldValue(v)
+	}
+	return fields, nil
+}
+
+func typedFieldValue(v string) any {
+	switch v {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f
+	}
+	return v
+}
+
+func appendQuery(apiPath string, fields map[string]any) string {
+	q := url.Values{}
+	for k, v := range fields {
+		if v == nil {
+			q.Set(k, "")
+			continue
+		}
+		q.Set(k, fmt.Sprint(v))
+	}
+	sep := "?"
+	if strings.Contains(apiPath, "?") {
+		sep = "&"
+	}
+	return apiPath + sep + q.Encode()
+}
+
+// applyJSONPath evaluates a small jq-like path: .a.b, .[0], .[] and
+// combinations such as .[].owner.login. Iterating with [] fans out.
+func applyJSONPath(doc any, expr string) ([]any, error) {
+	e := strings.TrimSpace(expr)
+	if e == "" || e == "." {
+		return []any{doc}, nil
+	}
+	if !strings.HasPrefix(e, ".") {
+		return nil, fmt.Errorf("филтер мора почети тачком: %s", expr)
+	}
+
+	current := []any{doc}
+	rest := e
+	for rest != "" {
+		var next []any
+		switch {
+		case strings.HasPrefix(rest, "[]") || strings.HasPrefix(rest, ".[]"):
+			rest = strings.TrimPrefix(strings.TrimPrefix(rest, "."), "[]")
+			for _, v := range current {
+				switch t := v.(type) {
+				case []any:
+					next = append(next, t...)
+				case map[string]any:
+					for _, item := range t {
+						next = append(next, item)
+					}
+				default:
+					return nil, fmt.Errorf("[] над вредношћу која није низ: %s", expr)
+				}
+			}
+		case strings.HasPrefix(rest, "[") || strings.HasPrefix(rest, ".["):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("незатворена заграда у филтеру: %s", expr)
+			}
+			idx, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("невалидан индекс у филтеру: %s", expr)
+			}
+			rest = rest[end+1:]
+			for _, v := range current {
+				arr, ok := v.([]any)
+				if !ok {
+					return nil, fmt.Errorf("индекс над вредношћу која није низ: %s", expr)
+				}
+				if idx < 0 {
+					idx += len(arr)
+				}
+				if idx < 0 || idx >= len(arr) {
+					next = append(next, nil)
+					continue
+				}
+				next = append(next, arr[idx])
+			}
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			if key == "" {
+				return nil, fmt.Errorf("празан кључ у филтеру: %s", expr)
+			}
+			for _, v := range current {
+				obj, ok := v.(map[string]any)
+				if !ok {
+					next = append(next, nil)
+					continue
+				}
+				next = append(next, obj[key])
+			}
+		default:
+			return nil, fmt.Errorf("невалидан филтер: %s", expr)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func printJSONValue(w io.Writer, v any) error {
+	switch t := v.(type) {
+	case string:
+		_, err := fmt.Fprintln(w, t)
+		return err
+	case nil:
+		_, err := fmt.Fprintln(w, "null")
+		return err
+	default:
+		data, err := json.MarshalIndent(t, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+}
+
+func printAPIUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s api [method] <path> [-f key=value]... [-F key=value]... [-H "Key: Value"]...
+         [--input <file|->] [--paginate] [-q <filter>] [--silent]
+
+Path је релативан на server_url/api/v1. {owner} и {repo} се замењују
+вредностима из gitcrn (па origin) remote-а тренутног репоа.
+
+-f шаље string, -F претвара true/false/null и бројеве. За GET/DELETE поља
+иду у query, иначе у JSON тело.
+
+Примери:
+  %s api user
+  %s api repos/{owner}/{repo}/issues --paginate -q '.[].title'
+  %s api POST repos/{owner}/{repo}/labels -f name=bug -f color=#ff0000
+  echo '{"body":"ok"}' | %s api POST repos/vltc/kapri/issues/1/comments --input -
+`, appName, appName, appName, appName, appName)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestApplyJSONPath(t *testing.T) {
+	var doc any
+	raw := `[{"name":"a","owner":{"login":"vltc"}},{"name":"b","owner":{"login":"crnbg"}}]`
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: ".[].name", want: "a,b"},
+		{expr: ".[1].owner.login", want: "crnbg"},
+		{expr: ".[-1].name", want: "b"},
+		{expr: ".[].owner.login", want: "vltc,crnbg"},
+	}
+	for _, tc := range tests {
+		values, err := applyJSONPath(doc, tc.expr)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.expr, err)
+		}
+		parts := make([]string, 0, len(values))
+		for _, v := range values {
+			parts = append(parts, fmt.Sprint(v))
+		}
+		if got := strings.Join(parts, ","); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.expr, got, tc.want)
+		}
+	}
+
+	if _, err := applyJSONPath(doc, "name"); err == nil {
+		t.Fatalf("expected error for filter without leading dot")
+	}
+}
+
+func TestParseAPIFields(t *testing.T) {
+	fields, err := parseAPIFields([]string{"title=1"}, []string{"count=3", "draft=true", "body=text"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields["title"] != "1" {
+		t.Fatalf("-f should keep strings: %#v", fields["title"])
+	}
+	if fields["count"] != int64(3) || fields["draft"] != true || fields["body"] != "text" {
+		t.Fatalf("unexpected typed fields: %#v", fields)
+	}
+
+	if _, err := parseAPIFields([]string{"novalue"}, nil); err == nil {
+		t.Fatalf("expected error for field without =")
+	}
+}
+
+func TestGiteaClientTokenOnlyForServer(t *testing.T) {
+	var got []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `[{"number":9}]`)
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/issues?page=2>; rel="next"`, other.URL))
+		fmt.Fprint(w, `[{"number":1}]`)
+	}))
+	defer srv.Close()
+
+	client := &giteaClient{serverURL: srv.URL, token: "secret"}
+	if _, err := client.raw(http.MethodGet, other.URL+"/api/v1/user", nil, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.raw(http.MethodGet, "issues", nil, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "" || got[1] != "" {
+		t.Fatalf("token leaked to another host: %q", got)
+	}
+}
+
+func TestGiteaClientRawPaginate(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"token is required"}`)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/repos/vltc/kapri/issues?page=2>; rel="next"`, srv.URL))
+			fmt.Fprint(w, `[{"number":1},{"number":2}]`)
+		case "2":
+			fmt.Fprint(w, `[{"number":3}]`)
+		}
+	}))
+	defer srv.Close()
+
+	client := &giteaClient{serverURL: srv.URL, token: "secret"}
+	data, err := client.raw(http.MethodGet, "/repos/vltc/kapri/issues", nil, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var items []map[string]int
+	if err := json.Unmarshal(data, &items); err != nil {
+		t.Fatalf("merged response is not an array: %v", err)
+	}
+	if len(items) != 3 || items[2]["number"] != 3 {
+		t.Fatalf("unexpected merged pages: %s", data)
+	}
+
+	// Endless rel="next" stops at maxAPIPages.
+	loop := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/items?page=x>; rel="next"`, r.Host))
+		fmt.Fprint(w, `[1]`)
+	}))
+	defer loop.Close()
+	looping := &giteaClient{serverURL: loop.URL, token: "secret"}
+	if _, err := looping.raw(http.MethodGet, "items", nil, nil, true); err == nil || !strings.Contains(err.Error(), "страница") {
+		t.Fatalf("expected page limit error, got %v", err)
+	}
+
+	bad := &giteaClient{serverURL: srv.URL, token: "wrong"}
+	_, err = bad.raw(http.MethodGet, "user", nil, nil, false)
+	if err == nil || !strings.Contains(err.Error(), "token is required") {
+		t.Fatalf("expected gitea message in error, got %v", err)
+	}
+}
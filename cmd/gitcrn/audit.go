@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// maxScannedFileSize keeps the token scan away from large tracked blobs.
+const maxScannedFileSize = 1 << 20
+
+// tokenAssignmentPattern finds a Gitea token (40 hex chars) assigned to the
+// variables gitcrn reads, for when the configured token itself is unknown.
+var tokenAssignmentPattern = regexp.MustCompile(`(?i)(GITCRN|GITEA)_TOKEN\s*[=:]\s*["']?[0-9a-f]{40}`)
+
+// sshOverrideKeys are the settings where a broader Host block can silently
+// win over the gitcrn block, since ssh uses the first value it sees.
+var sshOverrideKeys = []string{"hostname", "user", "port", "identityfile", "proxycommand", "proxyjump"}
+
+// doctorCheckSecurity looks for things that loosen what init set up. It never
+// prints the token; findings name only the file and line where it was seen.
+// config.toml permissions are checked by the config group.
+func doctorCheckSecurity(report *doctorReport) {
+	cfg, _ := loadAppConfig()
+	token := resolveToken(cfg)
+
+	if runtime.GOOS != "windows" {
+		auditSSHPermissions(report)
+	}
+	auditShellHistory(report, token)
+	auditTrackedFiles(report, token)
+	auditSSHHostOverrides(report, defaultHostAlias)
+}
+
+func auditSSHPermissions(report *doctorReport) {
+	sshDir := expandHomePath("~/.ssh")
+	info, err := os.Stat(sshDir)
+	if err != nil || !info.IsDir() {
+		return
+	}
+
+	perm := info.Mode().Perm()
+	switch {
+	case perm&0o022 != 0:
+		report.fail("security.ssh-dir", "~/.ssh дозволе", fmt.Sprintf("%s има дозволе %04o (други могу да мењају кључеве и конфиг)", sshDir, perm), "chmod 700 "+sshDir)
+		report.remedy("chmod 700 "+sshDir, fixChmod(sshDir, 0o700))
+	case perm&0o077 != 0:
+		report.warn("security.ssh-dir", "~/.ssh дозволе", fmt.Sprintf("%s има дозволе %04o", sshDir, perm), "chmod 700 "+sshDir)
+		report.remedy("chmod 700 "+sshDir, fixChmod(sshDir, 0o700))
+	default:
+		report.ok("security.ssh-dir", "~/.ssh дозволе", fmt.Sprintf("%04o", perm))
+	}
+
+	for _, key := range privateKeyPaths() {
+		info, err := os.Stat(key)
+		if err != nil {
+			continue
+		}
+		perm := info.Mode().Perm()
+		if perm&0o077 == 0 {
+			report.ok("security.ssh-key", "Приватни кључ", fmt.Sprintf("%s (%04o)", key, perm))
+			continue
+		}
+		// ssh refuses such a key with "UNPROTECTED PRIVATE KEY FILE".
+		report.fail("security.ssh-key", "Приватни кључ", fmt.Sprintf("%s има дозволе %04o (ssh га одбија)", key, perm), "chmod 600 "+key)
+		report.remedy("chmod 600 "+key, fixChmod(key, 0o600))
+	}
+}
+
+// privateKeyPaths lists the default identities plus the IdentityFile of the
+// gitcrn host, without duplicates.
+func privateKeyPaths() []string {
+	var candidates []string
+	for _, pub := range defaultPublicKeyCandidates() {
+		candidates = append(candidates, expandHomePath(strings.TrimSuffix(pub, ".pub")))
+	}
+	if configPath, err := sshConfigPath(); err == nil {
+		if resolved, err := resolveSSHHost(defaultHostAlias, configPath, sshSystemConfigPath()); err == nil {
+			for _, id := range resolved.identityFiles() {
+				candidates = append(candidates, strings.TrimSuffix(id, ".pub"))
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	var keys []string
+	for _, c := range candidates {
+		if seen[c] || !fileExists(c) {
+			continue
+		}
+		seen[c] = true
+		keys = append(keys, c)
+	}
+	return keys
+}
+
+func shellHistoryPaths() []string {
+	paths := []string{
+		"~/.bash_history",
+		"~/.zsh_history",
+		"~/.local/share/fish/fish_history",
+	}
+	if histFile := strings.TrimSpace(os.Getenv("HISTFILE")); histFile != "" {
+		paths = append(paths, histFile)
+	}
+	if appData := os.Getenv("APPDATA"); appData != "" {
+		paths = append(paths, filepath.Join(appData, "Microsoft", "Windows", "PowerShell", "PSReadLine", "ConsoleHost_history.txt"))
+	}
+
+	seen := map[string]bool{}
+	var out []string
+	for _, p := range paths {
+		p = expandHomePath(p)
+		if seen[p] || !fileExists(p) {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out
+}
+
+func auditShellHistory(report *doctorReport, token string) {
+	var hits []string
+	for _, p := range shellHistoryPaths() {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		if lines := tokenLines(data, token); len(lines) > 0 {
+			hits = append(hits, fmt.Sprintf("%s (ред %s)", p, joinInts(lines)))
+		}
+	}
+	if len(hits) == 0 {
+		report.ok("security.history", "Shell историја", "нема token-а")
+		return
+	}
+	report.fail("security.history", "Shell историја", "token је остао у: "+strings.Join(hits, ", "),
+		"обриши те редове из историје и направи нови token у Gitea (Settings → Applications)")
+}
+
+func auditTrackedFiles(report *doctorReport, token string) {
+	if !insideGitRepo() {
+		return
+	}
+	root := commandOutput("git", "rev-parse", "--show-toplevel")
+	out, err := exec.Command("git", "ls-files", "-z").Output()
+	if err != nil || root == "" {
+		return
+	}
+
+	var hits []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" {
+			continue
+		}
+		full := filepath.Join(root, filepath.FromSlash(name))
+		info, err := os.Stat(full)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxScannedFileSize {
+			continue
+		}
+		data, err := os.ReadFile(full)
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+		if lines := tokenLines(data, token); len(lines) > 0 {
+			hits = append(hits, fmt.Sprintf("%s:%s", name, joinInts(lines)))
+		}
+	}
+	if len(hits) == 0 {
+		report.ok("security.repo", "Token у репоу", "нема у праћеним фајловима")
+		return
+	}
+	report.fail("security.repo", "Token у репоу", "token у праћеним фајловима: "+strings.Join(hits, ", "),
+		"уклони token из фајлова (и из историје commit-а), направи нови token и користи GITCRN_TOKEN")
+}
+
+// tokenLines returns 1-based line numbers that contain token or a token
+// assignment to GITCRN_TOKEN/GITEA_TOKEN.
+func tokenLines(data []byte, token string) []int {
+	var lines []int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScannedFileSize)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := scanner.Text()
+		if (len(token) >= 8 && strings.Contains(line, token)) || tokenAssignmentPattern.MatchString(line) {
+			lines = append(lines, n)
+		}
+	}
+	return lines
+}
+
+func joinInts(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = fmt.Sprint(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+type sshHostOverride struct {
+	Patterns string
+	Line     int
+	Keys     []string
+}
+
+// findSSHHostOverrides reports Host blocks that match alias through a
+// wildcard and come before the alias's own block, listing the settings they
+// would take over.
+func findSSHHostOverrides(content, alias string) []sshHostOverride {
+	var (
+		overrides []sshHostOverride
+		current   *sshHostOverride
+	)
+	flush := func() {
+		if current != nil && len(current.Keys) > 0 {
+			overrides = append(overrides, *current)
+		}
+		current = nil
+	}
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if patterns, ok := parseHostLine(line); ok {
+			flush()
+			if hostPatternMatches(patterns, alias) {
+				// Blocks after the alias's own one cannot override it.
+				return overrides
+			}
+			if sshPatternsMatch(patterns, alias) {
+				current = &sshHostOverride{Patterns: strings.Join(patterns, " "), Line: i + 1}
+			}
+			continue
+		}
+		if fields := strings.Fields(line); current != nil && len(fields) >= 2 {
+			key := strings.ToLower(fields[0])
+			for _, k := range sshOverrideKeys {
+				if key == k && !containsString(current.Keys, fields[0]) {
+					current.Keys = append(current.Keys, fields[0])
+				}
+			}
+		}
+	}
+	flush()
+	return overrides
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func auditSSHHostOverrides(report *doctorReport, alias string) {
+	configPath, err := sshConfigPath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return
+	}
+
+	overrides := findSSHHostOverrides(normalizeNewlines(string(data)), alias)
+	if len(overrides) == 0 {
+		report.ok("security.ssh-override", "SSH Host *", "ниједан шири Host блок не мења "+alias)
+		return
+	}
+	var parts []string
+	for _, o := range overrides {
+		parts = append(parts, fmt.Sprintf("ред %d \"Host %s\" поставља %s", o.Line, o.Patterns, strings.Join(o.Keys, ", ")))
+	}
+	report.warn("security.ssh-override", "SSH Host *",
+		fmt.Sprintf("блокови пре Host %s имају предност (ssh узима прву вредност): %s", alias, strings.Join(parts, "; ")),
+		"помери Host "+alias+" блок изнад њих или те блокове на крај "+configPath)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestTokenLines(t *testing.T) {
+	data := []byte("git push\nexport GITCRN_TOKEN=0123456789abcdef0123456789abcdef01234567\ncurl -H 'Authorization: token s3cr3t-token'\nGITEA_TOKEN=short\n")
+	got := tokenLines(data, "s3cr3t-token")
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("unexpected lines: %v", got)
+	}
+	if got := tokenLines(data, "abc"); len(got) != 1 {
+		t.Fatalf("short token should not be searched literally: %v", got)
+	}
+}
+
+func TestFindSSHHostOverrides(t *testing.T) {
+	content := strings.Join([]string{
+		"Host *",
+		"  ServerAliveInterval 30",
+		"  User root",
+		"  IdentityFile ~/.ssh/work",
+		"",
+		"Host *.example.com",
+		"  User nobody",
+		"",
+		"Host gitcrn",
+		"  HostName 100.64.0.1",
+		"  User git",
+		"",
+		"Host *",
+		"  Port 2222",
+	}, "\n")
+
+	got := findSSHHostOverrides(content, "gitcrn")
+	if len(got) != 1 {
+		t.Fatalf("expected one override, got %+v", got)
+	}
+	if got[0].Line != 1 || got[0].Patterns != "*" || strings.Join(got[0].Keys, ",") != "User,IdentityFile" {
+		t.Fatalf("unexpected override: %+v", got[0])
+	}
+
+	if got := findSSHHostOverrides("Host * !gitcrn\n  User root\nHost gitcrn\n  User git\n", "gitcrn"); len(got) != 0 {
+		t.Fatalf("negated pattern should not override: %+v", got)
+	}
+}
+
+func TestAuditSSHPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX permissions")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	sshDir := filepath.Join(home, ".ssh")
+	if err := os.Mkdir(sshDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(sshDir, 0o775); err != nil {
+		t.Fatal(err)
+	}
+	key := filepath.Join(sshDir, "id_ed25519")
+	if err := os.WriteFile(key, []byte("key"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &doctorReport{}
+	auditSSHPermissions(r)
+	if len(r.Results) != 2 || r.Results[0].ID != "security.ssh-dir" || r.Results[1].ID != "security.ssh-key" {
+		t.Fatalf("unexpected results: %+v", r.Results)
+	}
+	for _, res := range r.Results {
+		if res.Severity != severityFail || !res.Fixable {
+			t.Fatalf("expected fixable failure: %+v", res)
+		}
+	}
+	if _, err := applyDoctorFixes(r, &doctorFixer{yes: true, out: io.Discard}); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(sshDir); info.Mode().Perm() != 0o700 {
+		t.Fatalf("~/.ssh not fixed: %04o", info.Mode().Perm())
+	}
+	if info, _ := os.Stat(key); info.Mode().Perm() != 0o600 {
+		t.Fatalf("key not fixed: %04o", info.Mode().Perm())
+	}
+}
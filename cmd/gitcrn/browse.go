@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+type browseTarget struct {
+	Issues   bool
+	Pulls    bool
+	Releases bool
+	Settings bool
+	Commit   string
+	Branch   string
+	Path     string
+	Line     int
+}
+
+func runBrowse(args []string) error {
+	fs := newFlagSet("browse")
+
+	issues := fs.Bool("issues", false, "Отвори issues")
+	pulls := fs.Bool("pulls", false, "Отвори pull request-ове")
+	releases := fs.Bool("releases", false, "Отвори releases")
+	settings := fs.Bool("settings", false, "Отвори подешавања репоа")
+	commit := fs.String("commit", "", "Отвори commit")
+	branch := fs.String("branch", "", "Грана за путању фајла")
+	printOnly := fs.Bool("print", false, "Само испиши URL")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printBrowseUsage(os.Stdout)
+			return nil
+		}
+		printBrowseUsage(os.Stderr)
+		return err
+	}
+	if len(positional) > 2 {
+		printBrowseUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(positional[2:], " "))
+	}
+
+	selected := 0
+	for _, on := range []bool{*issues, *pulls, *releases, *settings, strings.TrimSpace(*commit) != ""} {
+		if on {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return errors.New("изабери највише једно: --issues, --pulls, --releases, --settings или --commit")
+	}
+
+	repoArg, pathArg := splitBrowseArgs(positional)
+	if selected > 0 && pathArg != "" {
+		return errors.New("путања фајла се не може комбиновати са --issues, --pulls, --releases, --settings или --commit")
+	}
+
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return err
+	}
+	serverURL := resolveServerURL(cfg)
+
+	owner, repoName, err := resolveOwnerRepo(repoArg, serverURL)
+	if err != nil {
+		return err
+	}
+
+	target := browseTarget{
+		Issues:   *issues,
+		Pulls:    *pulls,
+		Releases: *releases,
+		Settings: *settings,
+		Commit:   strings.TrimSpace(*commit),
+		Branch:   strings.TrimSpace(*branch),
+	}
+
+	if pathArg != "" {
+		filePath, line, err := parsePathLine(pathArg)
+		if err != nil {
+			return err
+		}
+		if repoArg == "" {
+			filePath = repoRelativePath(filePath)
+		}
+		target.Path = filePath
+		target.Line = line
+		if target.Branch == "" {
+			target.Branch = strings.TrimSpace(commandOutput("git", "branch", "--show-current"))
+		}
+		if target.Branch == "" {
+			target.Branch = "HEAD"
+		}
+	}
+
+	pageURL := buildBrowseURL(serverURL, owner, repoName, target)
+	if *printOnly {
+		fmt.Println(pageURL)
+		return nil
+	}
+
+	fmt.Println(colorize("Отварам: "+pageURL, ansiCyan, stdoutColor))
+	return openBrowser(pageURL)
+}
+
+// splitBrowseArgs decides whether a lone argument is owner/repo or a path.
+// Anything that exists on disk, carries a :line suffix or does not look like
+// owner/repo is treated as a path inside the current checkout.
+func splitBrowseArgs(positional []string) (repoArg, pathArg string) {
+	switch len(positional) {
+	case 0:
+		return "", ""
+	case 1:
+		arg := positional[0]
+		if p, _, err := parsePathLine(arg); err == nil && fileOrDirExists(p) {
+			return "", arg
+		}
+		if strings.Contains(arg, ":") && !strings.Contains(arg, "/") {
+			return "", arg
+		}
+		if _, _, err := normalizeRepoSpec(arg); err == nil {
+			return arg, ""
+		}
+		return "", arg
+	default:
+		return positional[0], positional[1]
+	}
+}
+
+func parsePathLine(input string) (string, int, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return "", 0, errors.New("путања не сме бити празна")
+	}
+	if i := strings.LastIndex(s, ":"); i > 0 {
+		if n, err := strconv.Atoi(s[i+1:]); err == nil {
+			if n <= 0 {
+				return "", 0, fmt.Errorf("невалидна линија: %s", s[i+1:])
+			}
+			return s[:i], n, nil
+		}
+	}
+	return s, 0, nil
+}
+
+func repoRelativePath(p string) string {
+	prefix := strings.TrimSpace(commandOutput("git", "rev-parse", "--show-prefix"))
+	rel := filepath.ToSlash(filepath.Clean(filepath.Join(prefix, p)))
+	return strings.TrimPrefix(rel, "./")
+}
+
+func buildBrowseURL(serverURL, owner, repo string, t browseTarget) string {
+	base := fmt.Sprintf("%s/%s/%s", strings.TrimRight(serverURL, "/"), url.PathEscape(owner), url.PathEscape(repo))
+
+	switch {
+	case t.Issues:
+		return base + "/issues"
+	case t.Pulls:
+		return base + "/pulls"
+	case t.Releases:
+		return base + "/releases"
+	case t.Settings:
+		return base + "/settings"
+	case t.Commit != "":
+		return base + "/commit/" + url.PathEscape(t.Commit)
+	case t.Path != "":
+		segments := strings.Split(path.Clean(t.Path), "/")
+		for i, seg := range segments {
+			segments[i] = url.PathEscape(seg)
+		}
+		u := fmt.Sprintf("%s/src/branch/%s/%s", base, escapeBranch(t.Branch), strings.Join(segments, "/"))
+		if t.Line > 0 {
+			u += fmt.Sprintf("#L%d", t.Line)
+		}
+		return u
+	default:
+		return base
+	}
+}
+
+func escapeBranch(branch string) string {
+	parts := strings.Split(branch, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+func openBrowser(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		// cmd /c start would cut the URL at the first & (query strings).
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	case "darwin":
+		cmd = exec.Command("open", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("не могу да отворим browser (%v). URL: %s", err, target)
+	}
+	return nil
+}
+
+func fileOrDirExists(p string) bool {
+	if p == "" {
+		return false
+	}
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+func printBrowseUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s browse [owner/repo] [path[:line]] [--branch <грана>] [--print]
+  %s browse [owner/repo] --issues|--pulls|--releases|--settings [--print]
+  %s browse [owner/repo] --commit <sha> [--print]
+
+Без owner/repo, repo се чита из gitcrn (па origin) remote-а тренутног репоа.
+`, appName, appName, appName)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBuildBrowseURL(t *testing.T) {
+	base := "http://100.91.132.35:5000/"
+	tests := []struct {
+		name   string
+		target browseTarget
+		want   string
+	}{
+		{name: "repo", want: "http://100.91.132.35:5000/vltc/kapri"},
+		{name: "issues", target: browseTarget{Issues: true}, want: "http://100.91.132.35:5000/vltc/kapri/issues"},
+		{name: "pulls", target: browseTarget{Pulls: true}, want: "http://100.91.132.35:5000/vltc/kapri/pulls"},
+		{name: "releases", target: browseTarget{Releases: true}, want: "http://100.91.132.35:5000/vltc/kapri/releases"},
+		{name: "settings", target: browseTarget{Settings: true}, want: "http://100.91.132.35:5000/vltc/kapri/settings"},
+		{name: "commit", target: browseTarget{Commit: "abc123"}, want: "http://100.91.132.35:5000/vltc/kapri/commit/abc123"},
+		{
+			name:   "file with line",
+			target: browseTarget{Path: "cmd/gitcrn/main.go", Line: 42, Branch: "feature/x"},
+			want:   "http://100.91.132.35:5000/vltc/kapri/src/branch/feature/x/cmd/gitcrn/main.go#L42",
+		},
+		{
+			name:   "file with space",
+			target: browseTarget{Path: "docs/my notes.md", Branch: "main"},
+			want:   "http://100.91.132.35:5000/vltc/kapri/src/branch/main/docs/my%20notes.md",
+		},
+	}
+
+	for _, tc := range tests {
+		if got := buildBrowseURL(base, "vltc", "kapri", tc.target); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestParsePathLine(t *testing.T) {
+	p, line, err := parsePathLine("file.go:42")
+	if err != nil || p != "file.go" || line != 42 {
+		t.Fatalf("unexpected result: %q %d %v", p, line, err)
+	}
+
+	p, line, err = parsePathLine("README.md")
+	if err != nil || p != "README.md" || line != 0 {
+		t.Fatalf("unexpected result: %q %d %v", p, line, err)
+	}
+
+	if _, _, err := parsePathLine("file.go:0"); err == nil {
+		t.Fatalf("expected error for line 0")
+	}
+}
+
+func TestSplitBrowseArgs(t *testing.T) {
+	repo, p := splitBrowseArgs([]string{"vltc/kapri"})
+	if repo != "vltc/kapri" || p != "" {
+		t.Fatalf("expected repo argument, got repo=%q path=%q", repo, p)
+	}
+
+	repo, p = splitBrowseArgs([]string{"main.go:42"})
+	if repo != "" || p != "main.go:42" {
+		t.Fatalf("expected path argument, got repo=%q path=%q", repo, p)
+	}
+
+	repo, p = splitBrowseArgs([]string{"vltc/kapri", "README.md"})
+	if repo != "vltc/kapri" || p != "README.md" {
+		t.Fatalf("unexpected split: repo=%q path=%q", repo, p)
+	}
+}
+
+func TestParseFlagsAnywhere(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	printOnly := fs.Bool("print", false, "")
+
+	positional, err := parseFlagsAnywhere(fs, []string{"vltc/kapri", "--print", "main.go"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !*printOnly {
+		t.Fatalf("expected --print to be parsed after positional argument")
+	}
+	if strings.Join(positional, ",") != "vltc/kapri,main.go" {
+		t.Fatalf("unexpected positional args: %v", positional)
+	}
+}
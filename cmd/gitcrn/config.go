@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type configEntry struct {
+	Key   string
+	Value any
+}
+
+func runConfig(args []string) error {
+	if len(args) < 1 {
+		printConfigUsage(os.Stdout)
+		return nil
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		if len(args) > 1 {
+			if args[1] == "-h" || args[1] == "--help" {
+				printConfigUsage(os.Stdout)
+				return nil
+			}
+			printConfigUsage(os.Stderr)
+			return fmt.Errorf("неочекивани аргументи: %s", strings.Join(args[1:], " "))
+		}
+		return runConfigList()
+	case "path":
+		path, err := appConfigPath()
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	case "-h", "--help", "help":
+		printConfigUsage(os.Stdout)
+		return nil
+	default:
+		printConfigUsage(os.Stderr)
+		return fmt.Errorf("неподржана config подкоманда: %s", args[0])
+	}
+}
+
+func runConfigList() error {
+	path, err := appConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return err
+	}
+	entries := configEntries(cfg)
+
+	if jsonOutput() {
+		values := make(map[string]any, len(entries))
+		for _, e := range entries {
+			values[e.Key] = e.Value
+		}
+		return writeJSON(os.Stdout, struct {
+			Path   string         `json:"path"`
+			Exists bool           `json:"exists"`
+			Values map[string]any `json:"values"`
+		}{path, fileExists(path), values})
+	}
+
+	if outputFormat == "" {
+		if fileExists(path) {
+			fmt.Fprintln(os.Stderr, "# "+path)
+		} else {
+			fmt.Fprintln(os.Stderr, "# "+path+" не постоји, приказане су подразумеване вредности")
+		}
+	}
+	t := newTableWriter(os.Stdout, "КЉУЧ", "ВРЕДНОСТ")
+	for _, e := range entries {
+		t.Row(e.Key, fmt.Sprint(e.Value))
+	}
+	t.Flush()
+	return nil
+}
+
+// configEntries lists the effective settings, including defaults and the
+// token from the environment. The token itself is never printed.
+func configEntries(cfg appConfig) []configEntry {
+	interval := resolveUpdateCheckInterval(cfg).String()
+	return []configEntry{
+		{"server_url", resolveServerURL(cfg)},
+		{"token", maskToken(resolveToken(cfg))},
+		{"ssh_alias", cfg.SSHAlias},
+		{"ssh_host", cfg.SSHHost},
+		{"ssh_port", cfg.SSHPort},
+		{"ssh_user", cfg.SSHUser},
+		{"protocol", cfg.Protocol},
+		{"tailscale_node", cfg.TailscaleNode},
+		{"endpoint", cfg.Endpoint},
+		{"auto_endpoint", cfg.AutoEndpoint},
+		{"update_provider", fallback(cfg.UpdateProvider, updateProviderGitHub)},
+		{"update_owner", fallback(cfg.UpdateOwner, defaultUpdateOwner)},
+		{"update_repo", fallback(cfg.UpdateRepo, defaultUpdateRepo)},
+		{"update_channel", resolveUpdateChannel(cfg)},
+		{"update_check_interval", interval},
+		{"update_base_url", cfg.UpdateBaseURL},
+	}
+}
+
+// updateAppConfigValues writes values into config.toml; a missing file
+// starts from the default template.
+func updateAppConfigValues(configPath string, values []configEntry) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		return fmt.Errorf("креирање config директоријума: %w", err)
+	}
+	oldContent, newContent, err := planAppConfigChange(configPath, values)
+	if err != nil {
+		return err
+	}
+	if fileExists(configPath) && oldContent == newContent {
+		return nil
+	}
+	if err := os.WriteFile(configPath, []byte(newContent), 0o600); err != nil {
+		return fmt.Errorf("упис %s: %w", configPath, err)
+	}
+	return nil
+}
+
+// planAppConfigChange returns the current and the updated config.toml. The
+// current content is empty when the file does not exist yet.
+func planAppConfigChange(configPath string, values []configEntry) (string, string, error) {
+	oldContent := ""
+	base := defaultAppConfigContent()
+	data, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		oldContent = normalizeNewlines(string(data))
+		base = oldContent
+	case !errors.Is(err, os.ErrNotExist):
+		return "", "", fmt.Errorf("читање %s: %w", configPath, err)
+	}
+	return oldContent, setAppConfigValues(base, values), nil
+}
+
+// setAppConfigValues sets top-level keys in place, keeping comments,
+// unrelated lines and [[endpoint]] tables. Missing keys are added before the
+// first table.
+func setAppConfigValues(content string, values []configEntry) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	for _, v := range values {
+		formatted := v.Key + " = " + formatConfigValue(v.Value)
+		top := len(lines)
+		for i, raw := range lines {
+			if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+				top = i
+				break
+			}
+		}
+
+		replaced := false
+		for i, raw := range lines[:top] {
+			line := strings.TrimSpace(raw)
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, _, ok := strings.Cut(line, "=")
+			if ok && strings.EqualFold(strings.TrimSpace(key), v.Key) {
+				lines[i] = formatted
+				replaced = true
+				break
+			}
+		}
+		if replaced {
+			continue
+		}
+
+		// Keep the new key with the other top-level keys, above the blank
+		// lines and comments that introduce the first table.
+		at := top
+		for at > 0 && (strings.TrimSpace(lines[at-1]) == "" || strings.HasPrefix(strings.TrimSpace(lines[at-1]), "#")) {
+			at--
+		}
+		if at == 0 {
+			at = top
+		}
+		lines = append(lines[:at], append([]string{formatted}, lines[at:]...)...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func formatConfigValue(v any) string {
+	switch v := v.(type) {
+	case int, bool:
+		return fmt.Sprint(v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = fmt.Sprintf("%q", s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	default:
+		return fmt.Sprintf("%q", fmt.Sprint(v))
+	}
+}
+
+func maskToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return strings.Repeat("*", 8) + token[len(token)-4:]
+}
+
+func printConfigUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s config list [--json|--format plain]
+  %s config path
+
+list приказује важеће вредности (config.toml, env и подразумеване).
+Token се никад не исписује цео.
+`, appName, appName)
+}
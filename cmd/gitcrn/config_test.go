@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateAppConfigValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	original := "# gitcrn config\nserver_url = \"http://100.91.132.35:5000\"\ntoken = \"abc\" # лични\n\n# ssh_host = \"коментар\"\nssh_host = \"100.91.132.35\"\n"
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := updateAppConfigValues(path, []configEntry{
+		{"ssh_host", "gitea.tail1234.ts.net"},
+		{"ssh_port", 2222},
+		{"tailscale_node", "gitea"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := os.ReadFile(path)
+	want := "# gitcrn config\nserver_url = \"http://100.91.132.35:5000\"\ntoken = \"abc\" # лични\n\n# ssh_host = \"коментар\"\nssh_host = \"gitea.tail1234.ts.net\"\nssh_port = 2222\ntailscale_node = \"gitea\"\n"
+	if string(got) != want {
+		t.Fatalf("unexpected config:\n%s", got)
+	}
+
+	missing := filepath.Join(t.TempDir(), "gitcrn", "config.toml")
+	if err := updateAppConfigValues(missing, []configEntry{{"tailscale_node", "gitea"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(missing); err != nil {
+		t.Fatalf("missing config should be created from the template: %v", err)
+	}
+
+	withTables := filepath.Join(t.TempDir(), "config.toml")
+	original = "ssh_host = \"100.91.132.35\"\n\n# друге адресе\n[[endpoint]]\nname = \"lan\"\nssh_host = \"192.168.1.20\"\n"
+	if err := os.WriteFile(withTables, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := updateAppConfigValues(withTables, []configEntry{{"endpoint", "lan"}, {"ssh_host", "100.91.1.1"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = os.ReadFile(withTables)
+	want = "ssh_host = \"100.91.1.1\"\nendpoint = \"lan\"\n\n# друге адресе\n[[endpoint]]\nname = \"lan\"\nssh_host = \"192.168.1.20\"\n"
+	if string(got) != want {
+		t.Fatalf("keys inside tables must not change:\n%s", got)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\nx\n"
+	b := "one\ntwo\nthree\nFOUR\nfive\nsix\nseven\neight\nnine\nten\nx\neleven\n"
+
+	want := `--- old
++++ new
+@@ -1,7 +1,7 @@
+ one
+ two
+ three
+-four
++FOUR
+ five
+ six
+ seven
+@@ -9,3 +9,4 @@
+ nine
+ ten
+ x
++eleven
+`
+	if got := unifiedDiff("old", "new", a, b); got != want {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+	if got := unifiedDiff("old", "new", a, a); got != "" {
+		t.Fatalf("equal texts should have no diff: %q", got)
+	}
+
+	want = "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+Host gitcrn\n+    Port 222\n"
+	if got := unifiedDiff("old", "new", "", "Host gitcrn\n    Port 222\n"); got != want {
+		t.Fatalf("unexpected diff for a new file:\n%s", got)
+	}
+}
@@ -0,0 +1,791 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	severityOK   = "ok"
+	severityWarn = "warn"
+	severityFail = "fail"
+
+	defaultDoctorTimeout = 5 * time.Second
+)
+
+// doctorCheck is one group of checks selectable with --only.
+type doctorCheck struct {
+	Group string
+	Run   func(*doctorReport)
+}
+
+var doctorChecks = []doctorCheck{
+	{"tailscale", doctorCheckTailscale},
+	{"git", doctorCheckGitIdentity},
+	{"ssh", doctorCheckSSHConfig},
+	{"config", doctorCheckAppConfig},
+	{"security", doctorCheckSecurity},
+	{"net", doctorCheckConnectivity},
+}
+
+type doctorResult struct {
+	ID       string `json:"id"`
+	Group    string `json:"group"`
+	Severity string `json:"severity"`
+	Name     string `json:"name"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+	Fixable  bool   `json:"fixable,omitempty"`
+
+	remedy *doctorRemedy
+}
+
+// doctorRemedy is a fix that doctor --fix can apply itself.
+type doctorRemedy struct {
+	Summary string
+	Apply   func(*doctorFixer) (string, error)
+}
+
+type doctorFixResult struct {
+	ID      string `json:"id"`
+	Applied bool   `json:"applied"`
+	Message string `json:"message"`
+}
+
+// doctorFixer asks before each fix unless --yes was given. It keeps one
+// reader so answers piped on stdin are not lost between prompts.
+type doctorFixer struct {
+	yes bool
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (f *doctorFixer) confirm(prompt string) (bool, error) {
+	if f.yes {
+		return true, nil
+	}
+	return promptYesNo(f.out, f.in, prompt+" [y/N]: ")
+}
+
+// ask returns def without prompting under --yes.
+func (f *doctorFixer) ask(label, def string) (string, error) {
+	if f.yes {
+		return def, nil
+	}
+	return promptInput(f.out, f.in, label, def)
+}
+
+type doctorReport struct {
+	group   string
+	timeout time.Duration
+	Results []doctorResult
+}
+
+func (r *doctorReport) add(severity, id, name, message, fix string) {
+	r.Results = append(r.Results, doctorResult{
+		ID:       id,
+		Group:    r.group,
+		Severity: severity,
+		Name:     name,
+		Message:  message,
+		Fix:      fix,
+	})
+}
+
+func (r *doctorReport) ok(id, name, message string) {
+	r.add(severityOK, id, name, message, "")
+}
+
+func (r *doctorReport) warn(id, name, message, fix string) {
+	r.add(severityWarn, id, name, message, fix)
+}
+
+func (r *doctorReport) fail(id, name, message, fix string) {
+	r.add(severityFail, id, name, message, fix)
+}
+
+// remedy attaches an automatic fix to the last added result.
+func (r *doctorReport) remedy(summary string, apply func(*doctorFixer) (string, error)) {
+	if len(r.Results) == 0 {
+		return
+	}
+	last := &r.Results[len(r.Results)-1]
+	last.remedy = &doctorRemedy{Summary: summary, Apply: apply}
+	last.Fixable = true
+}
+
+func (r *doctorReport) count(severity string) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// passed reports whether the run should exit 0; with strict, warnings count
+// as failures.
+func (r *doctorReport) passed(strict bool) bool {
+	if r.count(severityFail) > 0 {
+		return false
+	}
+	return !strict || r.count(severityWarn) == 0
+}
+
+func runDoctor(args []string) error {
+	fs := newFlagSet("doctor")
+	strict := fs.Bool("strict", false, "Упозорења се рачунају као грешке")
+	only := fs.String("only", "", "Само ове групе (нпр. ssh,git)")
+	timeout := fs.Duration("timeout", defaultDoctorTimeout, "Timeout за мрежне провере")
+	fix := fs.Bool("fix", false, "Понуди аутоматске поправке")
+	yes := fs.Bool("yes", false, "Уз --fix примени све поправке без питања")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printDoctorUsage(os.Stdout)
+			return nil
+		}
+		printDoctorUsage(os.Stderr)
+		return err
+	}
+
+	if fs.NArg() != 0 {
+		printDoctorUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+
+	checks, err := selectDoctorChecks(*only)
+	if err != nil {
+		printDoctorUsage(os.Stderr)
+		return err
+	}
+
+	if *timeout <= 0 {
+		return errors.New("--timeout мора бити већи од 0")
+	}
+
+	if *yes && !*fix {
+		return errors.New("--yes иде уз --fix")
+	}
+	if *fix && !*yes && !isTerminal(os.Stdin) {
+		return errors.New("--fix без терминала захтева --yes")
+	}
+
+	report := runDoctorChecks(checks, *timeout)
+	var fixes []doctorFixResult
+	if *fix {
+		fixer := &doctorFixer{yes: *yes, in: stdin, out: humanOut()}
+		var err error
+		fixes, err = applyDoctorFixes(report, fixer)
+		if err != nil {
+			return err
+		}
+		if len(fixes) > 0 {
+			// Check again so the output and exit code reflect the fixed state.
+			report = runDoctorChecks(checks, *timeout)
+		}
+	}
+	if err := report.print(*strict, fixes); err != nil {
+		return err
+	}
+
+	if !report.passed(*strict) {
+		fails, warns := report.count(severityFail), report.count(severityWarn)
+		if *strict {
+			return fmt.Errorf("doctor: %d грешака, %d упозорења (--strict)", fails, warns)
+		}
+		return fmt.Errorf("doctor: %d грешака", fails)
+	}
+	return nil
+}
+
+func runDoctorChecks(checks []doctorCheck, timeout time.Duration) *doctorReport {
+	report := &doctorReport{timeout: timeout}
+	for _, c := range checks {
+		report.group = c.Group
+		c.Run(report)
+	}
+	return report
+}
+
+// applyDoctorFixes offers every available remedy for a failed or warned
+// check and reports each outcome as it happens.
+func applyDoctorFixes(report *doctorReport, fixer *doctorFixer) ([]doctorFixResult, error) {
+	var fixes []doctorFixResult
+	for _, res := range report.Results {
+		if res.Severity == severityOK || res.remedy == nil {
+			continue
+		}
+
+		if !fixer.yes {
+			fmt.Fprintf(fixer.out, "%s %s: %s\n", colorize("["+strings.ToUpper(res.Severity)+"]", ansiYellow, stdoutColor), res.Name, res.Message)
+		}
+		ok, err := fixer.confirm("Поправка: " + res.remedy.Summary + "?")
+		if err != nil {
+			return fixes, err
+		}
+		if !ok {
+			fmt.Fprintf(fixer.out, "%s %s\n", colorize("[SKIP]", ansiYellow, stdoutColor), res.remedy.Summary)
+			continue
+		}
+
+		msg, err := res.remedy.Apply(fixer)
+		if err != nil {
+			fmt.Fprintf(fixer.out, "%s %s: %v\n", colorize("[FIX FAILED]", ansiRed, stdoutColor), res.Name, err)
+			fixes = append(fixes, doctorFixResult{ID: res.ID, Applied: false, Message: err.Error()})
+			continue
+		}
+		fmt.Fprintf(fixer.out, "%s %s: %s\n", colorize("[FIXED]", ansiGreen, stdoutColor), res.Name, msg)
+		fixes = append(fixes, doctorFixResult{ID: res.ID, Applied: true, Message: msg})
+	}
+	return fixes, nil
+}
+
+func selectDoctorChecks(only string) ([]doctorCheck, error) {
+	if strings.TrimSpace(only) == "" {
+		return doctorChecks, nil
+	}
+
+	known := make(map[string]bool, len(doctorChecks))
+	for _, c := range doctorChecks {
+		known[c.Group] = true
+	}
+	wanted := make(map[string]bool)
+	for _, g := range splitList(only) {
+		g = strings.ToLower(g)
+		if !known[g] {
+			return nil, fmt.Errorf("непозната doctor група %q (доступне: %s)", g, strings.Join(doctorGroups(), ", "))
+		}
+		wanted[g] = true
+	}
+
+	selected := make([]doctorCheck, 0, len(wanted))
+	for _, c := range doctorChecks {
+		if wanted[c.Group] {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+func doctorGroups() []string {
+	groups := make([]string, 0, len(doctorChecks))
+	for _, c := range doctorChecks {
+		groups = append(groups, c.Group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+func (r *doctorReport) print(strict bool, fixes []doctorFixResult) error {
+	results := r.Results
+	if results == nil {
+		results = []doctorResult{}
+	}
+
+	if jsonOutput() {
+		return writeJSON(os.Stdout, struct {
+			OK      bool              `json:"ok"`
+			Strict  bool              `json:"strict"`
+			Summary map[string]int    `json:"summary"`
+			Checks  []doctorResult    `json:"checks"`
+			Fixes   []doctorFixResult `json:"fixes,omitempty"`
+		}{
+			OK:     r.passed(strict),
+			Strict: strict,
+			Summary: map[string]int{
+				severityOK:   r.count(severityOK),
+				severityWarn: r.count(severityWarn),
+				severityFail: r.count(severityFail),
+			},
+			Checks: results,
+			Fixes:  fixes,
+		})
+	}
+	if outputFormat == formatPlain {
+		t := newTableWriter(os.Stdout)
+		for _, res := range results {
+			t.Row(res.Severity, res.ID, res.Message, res.Fix)
+		}
+		return nil
+	}
+
+	fmt.Println(colorize("Провера окружења (doctor)", ansiCyan, stdoutColor))
+	for _, res := range results {
+		switch res.Severity {
+		case severityOK:
+			doctorOK(res.Name, res.Message)
+		case severityWarn:
+			doctorWarn(res.Name, res.Message)
+		default:
+			doctorFail(res.Name, res.Message)
+		}
+		if res.Fix != "" {
+			fmt.Printf("  → %s\n", res.Fix)
+		}
+	}
+	if r.count(severityWarn)+r.count(severityFail) > 0 && len(fixes) == 0 && r.hasRemedies() {
+		fmt.Printf("\nДео проблема може аутоматски: %s doctor --fix\n", appName)
+	}
+	fmt.Printf("\n%d OK, %d упозорења, %d грешака\n", r.count(severityOK), r.count(severityWarn), r.count(severityFail))
+	return nil
+}
+
+func (r *doctorReport) hasRemedies() bool {
+	for _, res := range r.Results {
+		if res.Severity != severityOK && res.remedy != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func doctorCheckTailscale(report *doctorReport) {
+	out, err := exec.Command("tailscale", "version").CombinedOutput()
+	if err != nil {
+		report.warn("tailscale.installed", "Tailscale", "није инсталиран или није у PATH-у", "инсталирај: https://tailscale.com/download")
+		return
+	}
+
+	firstLine := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if firstLine == "" {
+		firstLine = "доступан"
+	}
+	report.ok("tailscale.installed", "Tailscale", firstLine)
+	doctorCheckTailscaleStatus(report)
+}
+
+func doctorCheckGitIdentity(report *doctorReport) {
+	out, err := exec.Command("git", "--version").CombinedOutput()
+	if err != nil {
+		report.fail("git.installed", "Git", "није инсталиран или није у PATH-у", "инсталирај git: https://git-scm.com/downloads")
+		return
+	}
+	line := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if line == "" {
+		line = "доступан"
+	}
+	report.ok("git.installed", "Git", line)
+
+	nameGlobal := strings.TrimSpace(commandOutput("git", "config", "--global", "--get", "user.name"))
+	emailGlobal := strings.TrimSpace(commandOutput("git", "config", "--global", "--get", "user.email"))
+	nameLocal := strings.TrimSpace(commandOutput("git", "config", "--get", "user.name"))
+	emailLocal := strings.TrimSpace(commandOutput("git", "config", "--get", "user.email"))
+	hasLocal := nameLocal != "" || emailLocal != ""
+	hasGlobal := nameGlobal != "" || emailGlobal != ""
+
+	if hasLocal {
+		report.ok("git.identity.local", "Git идентитет (локални)", fmt.Sprintf("%s <%s>", fallback(nameLocal, "?"), fallback(emailLocal, "?")))
+	} else {
+		report.warn("git.identity.local", "Git идентитет (локални)", "није подешен у тренутном репозиторијуму",
+			"git config user.name \"Твоје Име\" && git config user.email \"ти@мејл\"")
+		if insideGitRepo() {
+			report.remedy("подеси user.name и user.email за овај репо", fixLocalGitIdentity(nameGlobal, emailGlobal))
+		}
+	}
+
+	const globalFix = "git config --global user.name \"Твоје Име\" && git config --global user.email \"ти@мејл\""
+	switch {
+	case hasGlobal:
+		report.ok("git.identity.global", "Git идентитет (глобални)", fmt.Sprintf("%s <%s>", fallback(nameGlobal, "?"), fallback(emailGlobal, "?")))
+	case hasLocal:
+		report.warn("git.identity.global", "Git идентитет (глобални)", "није подешен", globalFix)
+	default:
+		// Without any identity git refuses to commit.
+		report.fail("git.identity.global", "Git идентитет (глобални)", "није подешен ни локално ни глобално", globalFix)
+	}
+
+	doctorCheckGitRemote(report)
+}
+
+// doctorCheckGitRemote only applies inside a repository; elsewhere there is
+// nothing to check.
+func doctorCheckGitRemote(report *doctorReport) {
+	if !insideGitRepo() {
+		return
+	}
+	if remoteURL := commandOutput("git", "remote", "get-url", defaultHostAlias); remoteURL != "" {
+		report.ok("git.remote", "Remote "+defaultHostAlias, remoteURL)
+		return
+	}
+	report.warn("git.remote", "Remote "+defaultHostAlias, "тренутни репо нема "+defaultHostAlias+" remote", appName+" add owner/repo")
+	report.remedy("додај "+defaultHostAlias+" remote", fixGitRemote)
+}
+
+func insideGitRepo() bool {
+	return commandOutput("git", "rev-parse", "--is-inside-work-tree") == "true"
+}
+
+func doctorCheckSSHConfig(report *doctorReport) {
+	configPath, err := sshConfigPath()
+	if err != nil {
+		report.fail("ssh.config", "SSH конфиг", err.Error(), "")
+		return
+	}
+
+	if _, err := os.ReadFile(configPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			report.fail("ssh.config", "SSH конфиг", fmt.Sprintf("не постоји (%s)", configPath), appName+" init --default")
+			report.remedy("упиши Host "+defaultHostAlias+" блок у "+configPath, fixSSHHostBlock)
+			return
+		}
+		report.fail("ssh.config", "SSH конфиг", fmt.Sprintf("грешка читања %s: %v", configPath, err), "провери дозволе за "+configPath)
+		return
+	}
+
+	if info, err := os.Stat(configPath); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0o022 != 0 {
+		// OpenSSH refuses a config that others can write to.
+		report.fail("ssh.perms", "SSH конфиг дозволе", fmt.Sprintf("%s има дозволе %04o (ssh га одбија)", configPath, info.Mode().Perm()), "chmod 600 "+configPath)
+		report.remedy("chmod 600 "+configPath, fixChmod(configPath, 0o600))
+	}
+
+	// Resolve the host the way ssh does: Include, Host * defaults, wildcard
+	// and negated patterns, Match blocks, first value wins.
+	resolved, err := resolveSSHHost(defaultHostAlias, configPath, sshSystemConfigPath())
+	if err != nil {
+		report.warn("ssh.parse", "SSH конфиг", err.Error(), "ssh -G "+defaultHostAlias)
+	}
+	if !resolved.Matched && resolved.get("hostname") == "" {
+		msg := "није пронађен у ~/.ssh/config"
+		if len(resolved.Unsupported) > 0 {
+			msg += " (није процењено: " + strings.Join(resolved.Unsupported, ", ") + ")"
+		}
+		report.fail("ssh.host", "SSH host gitcrn", msg, appName+" init --default")
+		report.remedy("упиши Host "+defaultHostAlias+" блок у "+configPath, fixSSHHostBlock)
+		return
+	}
+
+	hostName := fallback(resolved.get("hostname"), "?")
+	user := fallback(resolved.get("user"), "?")
+	port := fallback(resolved.get("port"), "?")
+	msg := fmt.Sprintf("HostName=%s User=%s Port=%s", hostName, user, port)
+	if src := resolved.Sources["hostname"]; src != "" && !strings.HasPrefix(src, configPath+":") {
+		msg += " (HostName из " + src + ")"
+	}
+	report.ok("ssh.host", "SSH host gitcrn", msg)
+	doctorCheckSSHResolve(report, configPath, resolved)
+
+	pubPath := ""
+	for _, identity := range resolved.identityFiles() {
+		if pubPath = identityPublicKeyPath(identity); pubPath != "" {
+			break
+		}
+	}
+	if pubPath == "" {
+		pubPath = firstExistingPath(defaultPublicKeyCandidates()...)
+	}
+
+	if pubPath == "" {
+		report.fail("ssh.key", "SSH кључ", "ниједан .pub кључ није пронађен у ~/.ssh",
+			"ssh-keygen -t ed25519 -C \"ти@мејл\" и додај .pub кључ у Gitea подешавања")
+		return
+	}
+
+	keyType, comment, err := readPublicKeyInfo(pubPath)
+	if err != nil {
+		report.warn("ssh.key", "SSH кључ", fmt.Sprintf("%s (не могу да прочитам коментар: %v)", pubPath, err), "")
+		return
+	}
+
+	if comment == "" {
+		comment = "(без коментара)"
+	}
+	report.ok("ssh.key", "SSH кључ", fmt.Sprintf("%s [%s] коментар: %s", pubPath, keyType, comment))
+}
+
+// doctorCheckSSHResolve compares the resolved settings with `ssh -G`, which
+// also covers what gitcrn does not evaluate (Match exec, canonicalization).
+func doctorCheckSSHResolve(report *doctorReport, configPath string, resolved sshResolved) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		if len(resolved.Unsupported) > 0 {
+			report.warn("ssh.resolve", "ssh -G", "није процењено: "+strings.Join(resolved.Unsupported, ", "), "")
+		}
+		return
+	}
+
+	sshG, err := sshEffectiveConfig(configPath, defaultHostAlias, 5*time.Second)
+	if err != nil {
+		report.warn("ssh.resolve", "ssh -G", err.Error(), "ssh -G "+defaultHostAlias)
+		return
+	}
+	if diffs := sshDisagreements(resolved, sshG, defaultHostAlias, sshLocalUser()); len(diffs) > 0 {
+		report.warn("ssh.resolve", "ssh -G", "не слаже се: "+strings.Join(diffs, "; "), "ssh -G "+defaultHostAlias+" показује шта ssh стварно користи")
+		return
+	}
+	report.ok("ssh.resolve", "ssh -G", fmt.Sprintf("%s:%s се слаже", sshG["hostname"], sshG["port"]))
+}
+
+func doctorCheckAppConfig(report *doctorReport) {
+	path, err := appConfigPath()
+	if err != nil {
+		report.fail("config.file", "config.toml", err.Error(), "")
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			report.warn("config.file", "config.toml", fmt.Sprintf("не постоји (%s), користе се подразумеване вредности", path), appName+" generate config")
+			report.remedy("направи "+path, func(*doctorFixer) (string, error) {
+				if err := writeDefaultAppConfig(path); err != nil {
+					return "", err
+				}
+				return "креиран " + path + " (упиши token)", nil
+			})
+			return
+		}
+		report.fail("config.file", "config.toml", err.Error(), "")
+		return
+	}
+	report.ok("config.file", "config.toml", path)
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		if cfg, _ := loadAppConfig(); cfg.Token != "" {
+			report.fail("config.perms", "config.toml дозволе", fmt.Sprintf("%04o, а config садржи token који могу да прочитају и други", perm), "chmod 600 "+path+" и направи нови token")
+		} else {
+			report.warn("config.perms", "config.toml дозволе", fmt.Sprintf("%04o, а config може да садржи token", perm), "chmod 600 "+path)
+		}
+		report.remedy("chmod 600 "+path, fixChmod(path, 0o600))
+	}
+}
+
+func fixChmod(path string, mode os.FileMode) func(*doctorFixer) (string, error) {
+	return func(*doctorFixer) (string, error) {
+		if err := os.Chmod(path, mode); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s -> %04o", path, mode), nil
+	}
+}
+
+func fixSSHHostBlock(*doctorFixer) (string, error) {
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return "", err
+	}
+	path, _, err := upsertSSHConfig(defaultHostAlias, cfg.SSHHost, cfg.SSHUser, cfg.SSHPort)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Host %s -> %s:%d као %s у %s", defaultHostAlias, cfg.SSHHost, cfg.SSHPort, cfg.SSHUser, path), nil
+}
+
+func fixLocalGitIdentity(defName, defEmail string) func(*doctorFixer) (string, error) {
+	return func(f *doctorFixer) (string, error) {
+		name, err := f.ask("user.name", defName)
+		if err != nil {
+			return "", err
+		}
+		email, err := f.ask("user.email", defEmail)
+		if err != nil {
+			return "", err
+		}
+		name, email = strings.TrimSpace(name), strings.TrimSpace(email)
+		if name == "" || email == "" {
+			return "", errors.New("име и мејл су обавезни (покрени --fix без --yes да их упишеш)")
+		}
+		for key, val := range map[string]string{"user.name": name, "user.email": email} {
+			if out, err := exec.Command("git", "config", key, val).CombinedOutput(); err != nil {
+				return "", fmt.Errorf("git config %s: %s", key, strings.TrimSpace(string(out)))
+			}
+		}
+		return fmt.Sprintf("%s <%s>", name, email), nil
+	}
+}
+
+func fixGitRemote(f *doctorFixer) (string, error) {
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return "", err
+	}
+	def := ""
+	if owner, repo, err := inferOwnerRepo(resolveServerURL(cfg)); err == nil {
+		def = owner + "/" + repo
+	}
+	spec, err := f.ask("owner/repo", def)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(spec) == "" {
+		return "", errors.New("не могу да одредим owner/repo из origin-а (покрени --fix без --yes да га упишеш)")
+	}
+	repoURL, err := resolveRepoURL(cfg, spec, false)
+	if err != nil {
+		return "", err
+	}
+	if out, err := exec.Command("git", "remote", "add", defaultHostAlias, repoURL).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git remote add: %s", strings.TrimSpace(string(out)))
+	}
+	return defaultHostAlias + " -> " + repoURL, nil
+}
+
+// doctorCheckConnectivity walks the path a push takes: TCP to the SSH port,
+// the Gitea API, the token, and finally an SSH login as git.
+func doctorCheckConnectivity(report *doctorReport) {
+	cfg, err := loadAppConfig()
+	if err != nil {
+		report.fail("net.config", "Config", err.Error(), "провери ~/.config/gitcrn/config.toml")
+		return
+	}
+
+	tcpOK := doctorCheckTCP(report, cfg.SSHHost, cfg.SSHPort)
+	if doctorCheckAPI(report, resolveServerURL(cfg)) {
+		doctorCheckToken(report, resolveServerURL(cfg), resolveToken(cfg))
+	} else {
+		report.warn("net.token", "Gitea token", "прескочено: API није доступан", "")
+	}
+	if tcpOK {
+		doctorCheckSSHLogin(report, cfg.SSHAlias)
+	} else {
+		report.warn("net.ssh", "SSH пријава", "прескочено: SSH порт није доступан", "")
+	}
+}
+
+func doctorCheckTCP(report *doctorReport, host string, port int) bool {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, report.timeout)
+	if err != nil {
+		report.fail("net.tcp", "SSH порт", fmt.Sprintf("%s: %v", addr, err),
+			"провери да ли је Tailscale повезан (tailscale status) и ssh_host/ssh_port у config.toml")
+		return false
+	}
+	conn.Close()
+	report.ok("net.tcp", "SSH порт", fmt.Sprintf("%s доступан за %s", addr, latency(start)))
+	return true
+}
+
+func doctorCheckAPI(report *doctorReport, serverURL string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), report.timeout)
+	defer cancel()
+
+	endpoint := serverURL + "/api/v1/version"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		report.fail("net.api", "Gitea API", err.Error(), "провери server_url у config.toml")
+		return false
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", appName)
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		report.fail("net.api", "Gitea API", err.Error(), "провери server_url у config.toml и да ли је сервер покренут")
+		return false
+	}
+	defer resp.Body.Close()
+	elapsed := latency(start)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		report.fail("net.api", "Gitea API", fmt.Sprintf("%s: status %d", endpoint, resp.StatusCode), "провери да server_url показује на Gitea")
+		return false
+	}
+	var payload struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil || payload.Version == "" {
+		report.fail("net.api", "Gitea API", endpoint+": одговор није Gitea верзија", "провери да server_url показује на Gitea")
+		return false
+	}
+	report.ok("net.api", "Gitea API", fmt.Sprintf("Gitea %s на %s (%s)", payload.Version, serverURL, elapsed))
+	return true
+}
+
+func doctorCheckToken(report *doctorReport, serverURL, token string) {
+	if token == "" {
+		report.warn("net.token", "Gitea token", "није подешен (create repo, issue, pr и release неће радити)",
+			"направи token у Gitea: Settings → Applications, па постави GITCRN_TOKEN или token у config.toml")
+		return
+	}
+	start := time.Now()
+	login, err := giteaCurrentUser(serverURL, token)
+	if err != nil {
+		report.fail("net.token", "Gitea token", err.Error(), "token је можда опозван или истекао; направи нови")
+		return
+	}
+	report.ok("net.token", "Gitea token", fmt.Sprintf("пријављен као %s (%s)", login, latency(start)))
+}
+
+func doctorCheckSSHLogin(report *doctorReport, alias string) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		report.fail("net.ssh", "SSH пријава", "ssh није у PATH-у", "инсталирај OpenSSH клијент")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), report.timeout*2)
+	defer cancel()
+	seconds := int(report.timeout / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	cmd := exec.CommandContext(ctx, "ssh", "-T",
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout="+strconv.Itoa(seconds),
+		alias)
+
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	elapsed := latency(start)
+	text := strings.TrimSpace(string(out))
+
+	// Gitea answers a successful login with a greeting and a non-zero exit,
+	// because it does not provide a shell.
+	if strings.Contains(text, "successfully authenticated") {
+		report.ok("net.ssh", "SSH пријава", fmt.Sprintf("ssh -T %s (%s): %s", alias, elapsed, firstOutputLine(text)))
+		return
+	}
+	if ctx.Err() != nil {
+		report.fail("net.ssh", "SSH пријава", fmt.Sprintf("ssh -T %s: timeout после %s", alias, elapsed), "провери мрежу и Host блок (gitcrn init --default)")
+		return
+	}
+	msg := firstOutputLine(text)
+	if msg == "" && err != nil {
+		msg = err.Error()
+	}
+	fix := "провери Host блок (gitcrn init --default)"
+	if strings.Contains(text, "Permission denied") {
+		fix = "додај свој .pub кључ у Gitea: Settings → SSH / GPG Keys"
+	} else if strings.Contains(text, "Host key verification failed") {
+		fix = "повежи се једном ручно (ssh -T " + alias + ") и прихвати host кључ"
+	}
+	report.fail("net.ssh", "SSH пријава", fmt.Sprintf("ssh -T %s: %s", alias, msg), fix)
+}
+
+func latency(start time.Time) time.Duration {
+	return time.Since(start).Round(time.Millisecond)
+}
+
+func firstOutputLine(s string) string {
+	return strings.TrimSpace(strings.SplitN(strings.TrimSpace(s), "\n", 2)[0])
+}
+
+func doctorOK(name, details string) {
+	fmt.Printf("%s %s: %s\n", colorize("[OK]", ansiGreen, stdoutColor), name, details)
+}
+
+func doctorWarn(name, details string) {
+	fmt.Printf("%s %s: %s\n", colorize("[WARN]", ansiYellow, stdoutColor), name, details)
+}
+
+func doctorFail(name, details string) {
+	fmt.Printf("%s %s: %s\n", colorize("[FAIL]", ansiRed, stdoutColor), name, details)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelectDoctorChecks(t *testing.T) {
+	all, err := selectDoctorChecks("")
+	if err != nil || len(all) != len(doctorChecks) {
+		t.Fatalf("empty --only should select everything, got %d, %v", len(all), err)
+	}
+
+	got, err := selectDoctorChecks("ssh, GIT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Group != "git" || got[1].Group != "ssh" {
+		t.Fatalf("expected git and ssh in registry order, got %+v", got)
+	}
+
+	if _, err := selectDoctorChecks("ssh,dns"); err == nil {
+		t.Fatalf("expected error for unknown group")
+	}
+}
+
+func TestDoctorReportPassed(t *testing.T) {
+	r := &doctorReport{group: "git"}
+	r.ok("git.installed", "Git", "git version 2.45")
+	if !r.passed(false) || !r.passed(true) {
+		t.Fatalf("only OK results must pass")
+	}
+
+	r.warn("git.identity.local", "Git", "није подешен", "git config user.name ...")
+	if !r.passed(false) {
+		t.Fatalf("warnings must pass without --strict")
+	}
+	if r.passed(true) {
+		t.Fatalf("warnings must fail with --strict")
+	}
+
+	r.fail("ssh.host", "SSH", "није пронађен", "gitcrn init --default")
+	if r.passed(false) {
+		t.Fatalf("failures must not pass")
+	}
+	if got := r.Results[2].Group; got != "git" {
+		t.Fatalf("result should carry the current group, got %q", got)
+	}
+	if r.count(severityWarn) != 1 || r.count(severityFail) != 1 || r.count(severityOK) != 1 {
+		t.Fatalf("unexpected counts: %+v", r.Results)
+	}
+}
+
+func TestDoctorCheckTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	r := &doctorReport{timeout: time.Second}
+	if !doctorCheckTCP(r, "127.0.0.1", port) {
+		t.Fatalf("expected open port to pass: %+v", r.Results)
+	}
+	ln.Close()
+
+	r = &doctorReport{timeout: time.Second}
+	if doctorCheckTCP(r, "127.0.0.1", port) {
+		t.Fatalf("expected closed port to fail")
+	}
+	if res := r.Results[0]; res.ID != "net.tcp" || res.Severity != severityFail || res.Fix == "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestDoctorCheckAPIAndToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/version":
+			fmt.Fprint(w, `{"version":"1.22.3"}`)
+		case "/api/v1/user":
+			if r.Header.Get("Authorization") != "token good" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"message":"invalid token"}`)
+				return
+			}
+			fmt.Fprint(w, `{"login":"vltc"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	r := &doctorReport{timeout: 2 * time.Second}
+	if !doctorCheckAPI(r, srv.URL) {
+		t.Fatalf("expected API check to pass: %+v", r.Results)
+	}
+	if !strings.Contains(r.Results[0].Message, "1.22.3") {
+		t.Fatalf("version missing from message: %q", r.Results[0].Message)
+	}
+
+	doctorCheckToken(r, srv.URL, "good")
+	doctorCheckToken(r, srv.URL, "bad")
+	doctorCheckToken(r, srv.URL, "")
+	want := []string{severityOK, severityOK, severityFail, severityWarn}
+	for i, sev := range want {
+		if r.Results[i].Severity != sev {
+			t.Fatalf("result %d: got %s, want %s (%+v)", i, r.Results[i].Severity, sev, r.Results[i])
+		}
+	}
+	if !strings.Contains(r.Results[1].Message, "vltc") {
+		t.Fatalf("login missing from message: %q", r.Results[1].Message)
+	}
+
+	r = &doctorReport{timeout: 2 * time.Second}
+	if doctorCheckAPI(r, srv.URL+"/nope") {
+		t.Fatalf("expected API check to fail on 404")
+	}
+}
+
+func TestApplyDoctorFixesPrompts(t *testing.T) {
+	r := &doctorReport{group: "config"}
+	applied := map[string]bool{}
+	for _, id := range []string{"a", "b", "c"} {
+		id := id
+		r.warn(id, id, "проблем", "")
+		r.remedy("поправи "+id, func(f *doctorFixer) (string, error) {
+			applied[id] = true
+			return "готово", nil
+		})
+	}
+	r.ok("d", "d", "у реду")
+
+	// All answers arrive in one read; a shared reader must not drop them.
+	var out strings.Builder
+	fixer := &doctorFixer{in: bufio.NewReader(strings.NewReader("y\nn\nда\n")), out: &out}
+	fixes, err := applyDoctorFixes(r, fixer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !applied["a"] || applied["b"] || !applied["c"] {
+		t.Fatalf("unexpected applied set: %v", applied)
+	}
+	if len(fixes) != 2 || fixes[0].ID != "a" || fixes[1].ID != "c" || !fixes[1].Applied {
+		t.Fatalf("unexpected fix results: %+v", fixes)
+	}
+	if !strings.Contains(out.String(), "[SKIP]") {
+		t.Fatalf("skipped fix should be reported:\n%s", out.String())
+	}
+}
+
+func TestDoctorCheckAppConfigFixesPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX permissions")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	r := &doctorReport{}
+	doctorCheckAppConfig(r)
+	if len(r.Results) != 1 || r.Results[0].ID != "config.file" || !r.Results[0].Fixable {
+		t.Fatalf("missing config should be a fixable warning: %+v", r.Results)
+	}
+	fixes, err := applyDoctorFixes(r, &doctorFixer{yes: true, out: io.Discard})
+	if err != nil || len(fixes) != 1 || !fixes[0].Applied {
+		t.Fatalf("config fix failed: %+v, %v", fixes, err)
+	}
+
+	path := filepath.Join(home, ".config", "gitcrn", "config.toml")
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r = &doctorReport{}
+	doctorCheckAppConfig(r)
+	if len(r.Results) != 2 || r.Results[1].ID != "config.perms" {
+		t.Fatalf("expected permission warning: %+v", r.Results)
+	}
+	if _, err := applyDoctorFixes(r, &doctorFixer{yes: true, out: io.Discard}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 after fix, got %v, %v", info.Mode().Perm(), err)
+	}
+}
+
+func TestDoctorCheckSSHConfigUniqueIDs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".ssh", "config")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// An Include of itself cannot be resolved.
+	if err := os.WriteFile(path, []byte("Host gitcrn\n    HostName 1.2.3.4\n    Include "+path+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &doctorReport{}
+	doctorCheckSSHConfig(r)
+	seen := map[string]bool{}
+	for _, res := range r.Results {
+		if seen[res.ID] {
+			t.Fatalf("duplicate check id %s: %+v", res.ID, r.Results)
+		}
+		seen[res.ID] = true
+	}
+	if !seen["ssh.parse"] {
+		t.Fatalf("expected ssh.parse warning: %+v", r.Results)
+	}
+}
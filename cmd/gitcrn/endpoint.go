@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProbeTimeout      = 3 * time.Second
+	autoEndpointProbeTimeout = 2 * time.Second
+)
+
+// endpointConfig is one [[endpoint]] table: another address of the same
+// server, such as its LAN IP or public DNS name.
+type endpointConfig struct {
+	Name      string `json:"name"`
+	SSHHost   string `json:"ssh_host"`
+	SSHPort   int    `json:"ssh_port"`
+	ServerURL string `json:"server_url"`
+}
+
+func (e *endpointConfig) set(key, val string) {
+	switch key {
+	case "name":
+		e.Name = val
+	case "ssh_host":
+		e.SSHHost = val
+	case "ssh_port":
+		if n, err := strconv.Atoi(val); err == nil && n > 0 && n <= 65535 {
+			e.SSHPort = n
+		}
+	case "server_url":
+		e.ServerURL = strings.TrimRight(val, "/")
+	}
+}
+
+// applyEndpoints fills endpoint defaults from the top-level settings and, if
+// an endpoint is selected, makes its address the effective one.
+func (c *appConfig) applyEndpoints() {
+	valid := c.Endpoints[:0]
+	for _, e := range c.Endpoints {
+		if e.SSHHost == "" {
+			continue
+		}
+		if e.Name == "" {
+			e.Name = e.SSHHost
+		}
+		if e.SSHPort == 0 {
+			e.SSHPort = c.SSHPort
+		}
+		if e.ServerURL == "" {
+			e.ServerURL = serverURLWithHost(c.ServerURL, e.SSHHost)
+		}
+		valid = append(valid, e)
+	}
+	c.Endpoints = valid
+
+	if e, ok := c.findEndpoint(c.Endpoint); ok {
+		c.SSHHost = e.SSHHost
+		c.SSHPort = e.SSHPort
+		c.ServerURL = e.ServerURL
+	}
+}
+
+func (c appConfig) findEndpoint(name string) (endpointConfig, bool) {
+	if name == "" {
+		return endpointConfig{}, false
+	}
+	for _, e := range c.Endpoints {
+		if strings.EqualFold(e.Name, name) {
+			return e, true
+		}
+	}
+	return endpointConfig{}, false
+}
+
+type endpointProbe struct {
+	Endpoint   endpointConfig
+	SSHLatency time.Duration
+	SSHError   string
+	APILatency time.Duration
+	APIError   string
+}
+
+type endpointProbeJSON struct {
+	endpointConfig
+	SSHLatencyMS int64  `json:"ssh_latency_ms,omitempty"`
+	SSHError     string `json:"ssh_error,omitempty"`
+	APILatencyMS int64  `json:"api_latency_ms,omitempty"`
+	APIError     string `json:"api_error,omitempty"`
+}
+
+func (p endpointProbe) sshOK() bool { return p.SSHError == "" }
+func (p endpointProbe) apiOK() bool { return p.APIError == "" }
+
+// probeEndpoints checks the SSH port and the Gitea API of every endpoint in
+// parallel, so the total time is one timeout at most.
+func probeEndpoints(endpoints []endpointConfig, timeout time.Duration) []endpointProbe {
+	probes := make([]endpointProbe, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e endpointConfig) {
+			defer wg.Done()
+			p := endpointProbe{Endpoint: e}
+			if d, err := probeTCP(e.SSHHost, e.SSHPort, timeout); err != nil {
+				p.SSHError = err.Error()
+			} else {
+				p.SSHLatency = d
+			}
+			if d, err := probeAPI(e.ServerURL, timeout); err != nil {
+				p.APIError = err.Error()
+			} else {
+				p.APILatency = d
+			}
+			probes[i] = p
+		}(i, e)
+	}
+	wg.Wait()
+	return probes
+}
+
+func probeTCP(host string, port int, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+	return latency(start), nil
+}
+
+func probeAPI(serverURL string, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL+"/api/v1/version", nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", appName)
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return latency(start), nil
+}
+
+// pickEndpoint keeps the configured order as preference: the first endpoint
+// with both SSH and API wins, otherwise the first one the protocol in use
+// can work with.
+func pickEndpoint(probes []endpointProbe, protocol string) (int, bool) {
+	for i, p := range probes {
+		if p.sshOK() && p.apiOK() {
+			return i, true
+		}
+	}
+	for i, p := range probes {
+		if (protocol == "https" && p.apiOK()) || (protocol != "https" && p.sshOK()) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// switchEndpoint points the managed Host block at e and records it as the
+// selected endpoint in config.toml.
+func switchEndpoint(cfg appConfig, e endpointConfig) error {
+	if _, _, err := upsertSSHConfig(defaultHostAlias, e.SSHHost, cfg.SSHUser, e.SSHPort); err != nil {
+		return err
+	}
+	configPath, err := appConfigPath()
+	if err != nil {
+		return err
+	}
+	return updateAppConfigValues(configPath, []configEntry{{"endpoint", e.Name}})
+}
+
+// autoSelectEndpoint runs before clone/push/pull when auto_endpoint is on.
+// The selected endpoint is kept while it answers; otherwise the first
+// reachable one takes over. Failure to find one is only a warning, so git
+// still reports the real error.
+func autoSelectEndpoint(cfg appConfig) appConfig {
+	if !cfg.AutoEndpoint || len(cfg.Endpoints) == 0 {
+		return cfg
+	}
+	if current, ok := cfg.findEndpoint(cfg.Endpoint); ok {
+		var err error
+		if cfg.Protocol == "https" {
+			_, err = probeAPI(current.ServerURL, autoEndpointProbeTimeout)
+		} else {
+			_, err = probeTCP(current.SSHHost, current.SSHPort, autoEndpointProbeTimeout)
+		}
+		if err == nil {
+			return cfg
+		}
+	}
+
+	probes := probeEndpoints(cfg.Endpoints, autoEndpointProbeTimeout)
+	i, ok := pickEndpoint(probes, cfg.Protocol)
+	if !ok {
+		fmt.Fprintln(os.Stderr, colorize("Упозорење: ниједан endpoint није доступан (gitcrn endpoint probe)", ansiYellow, stderrColor))
+		return cfg
+	}
+	e := probes[i].Endpoint
+	if strings.EqualFold(e.Name, cfg.Endpoint) {
+		return cfg
+	}
+	if err := switchEndpoint(cfg, e); err != nil {
+		fmt.Fprintln(os.Stderr, colorize("Упозорење: промена endpoint-а: "+err.Error(), ansiYellow, stderrColor))
+		return cfg
+	}
+	fmt.Fprintln(os.Stderr, colorize(fmt.Sprintf("Endpoint: %s (%s:%d)", e.Name, e.SSHHost, e.SSHPort), ansiCyan, stderrColor))
+	cfg.Endpoint = e.Name
+	cfg.applyEndpoints()
+	return cfg
+}
+
+func runEndpoint(args []string) error {
+	if len(args) < 1 {
+		printEndpointUsage(os.Stdout)
+		return nil
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		if len(args) > 1 {
+			printEndpointUsage(os.Stderr)
+			return fmt.Errorf("неочекивани аргументи: %s", strings.Join(args[1:], " "))
+		}
+		return runEndpointList()
+	case "probe":
+		return runEndpointProbe(args[1:])
+	case "use":
+		if len(args) != 2 {
+			printEndpointUsage(os.Stderr)
+			return errors.New("endpoint use тражи име endpoint-а")
+		}
+		return runEndpointUse(args[1])
+	case "-h", "--help", "help":
+		printEndpointUsage(os.Stdout)
+		return nil
+	default:
+		printEndpointUsage(os.Stderr)
+		return fmt.Errorf("неподржана endpoint подкоманда: %s", args[0])
+	}
+}
+
+func loadEndpoints() (appConfig, error) {
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return cfg, err
+	}
+	if len(cfg.Endpoints) == 0 {
+		path, _ := appConfigPath()
+		return cfg, fmt.Errorf("нема [[endpoint]] табела у %s", path)
+	}
+	return cfg, nil
+}
+
+func runEndpointList() error {
+	cfg, err := loadEndpoints()
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		return writeJSON(os.Stdout, struct {
+			Selected  string           `json:"selected"`
+			Auto      bool             `json:"auto"`
+			Endpoints []endpointConfig `json:"endpoints"`
+		}{cfg.Endpoint, cfg.AutoEndpoint, cfg.Endpoints})
+	}
+
+	t := newTableWriter(os.Stdout, "", "ИМЕ", "SSH", "SERVER_URL")
+	for _, e := range cfg.Endpoints {
+		t.Row(selectedMark(e, cfg.Endpoint), e.Name, net.JoinHostPort(e.SSHHost, strconv.Itoa(e.SSHPort)), e.ServerURL)
+	}
+	t.Flush()
+	return nil
+}
+
+func runEndpointProbe(args []string) error {
+	fs := newFlagSet("endpoint probe")
+	timeout := fs.Duration("timeout", defaultProbeTimeout, "Timeout по провери")
+	noSwitch := fs.Bool("no-switch", false, "Само измери, не мењај endpoint")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printEndpointUsage(os.Stdout)
+			return nil
+		}
+		printEndpointUsage(os.Stderr)
+		return err
+	}
+	if fs.NArg() != 0 {
+		printEndpointUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := loadEndpoints()
+	if err != nil {
+		return err
+	}
+	probes := probeEndpoints(cfg.Endpoints, *timeout)
+	i, ok := pickEndpoint(probes, cfg.Protocol)
+
+	selected := ""
+	if ok {
+		selected = probes[i].Endpoint.Name
+	}
+	if jsonOutput() {
+		out := make([]endpointProbeJSON, len(probes))
+		for i, p := range probes {
+			out[i] = endpointProbeJSON{p.Endpoint, p.SSHLatency.Milliseconds(), p.SSHError, p.APILatency.Milliseconds(), p.APIError}
+		}
+		if err := writeJSON(os.Stdout, struct {
+			Selected string              `json:"selected"`
+			Probes   []endpointProbeJSON `json:"probes"`
+		}{selected, out}); err != nil {
+			return err
+		}
+	} else {
+		t := newTableWriter(os.Stdout, "", "ИМЕ", "SSH", "API")
+		for _, p := range probes {
+			t.Row(selectedMark(p.Endpoint, selected), p.Endpoint.Name, t.Cell(probeResult(p.SSHLatency, p.SSHError), 50), t.Cell(probeResult(p.APILatency, p.APIError), 50))
+		}
+		t.Flush()
+	}
+
+	if !ok {
+		return errors.New("ниједан endpoint није доступан")
+	}
+	if *noSwitch {
+		return nil
+	}
+	if strings.EqualFold(selected, cfg.Endpoint) {
+		fmt.Fprintf(humanOut(), "Endpoint %s је већ изабран\n", selected)
+		return nil
+	}
+	if err := switchEndpoint(cfg, probes[i].Endpoint); err != nil {
+		return err
+	}
+	fmt.Fprintln(humanOut(), colorize("Endpoint: "+selected+" (Host "+defaultHostAlias+" и server_url ажурирани)", ansiGreen, stdoutColor))
+	return nil
+}
+
+func runEndpointUse(name string) error {
+	cfg, err := loadEndpoints()
+	if err != nil {
+		return err
+	}
+	e, ok := cfg.findEndpoint(name)
+	if !ok {
+		return fmt.Errorf("непознат endpoint: %s", name)
+	}
+	if err := switchEndpoint(cfg, e); err != nil {
+		return err
+	}
+	fmt.Fprintln(humanOut(), colorize(fmt.Sprintf("Endpoint: %s (%s:%d, %s)", e.Name, e.SSHHost, e.SSHPort, e.ServerURL), ansiGreen, stdoutColor))
+	return nil
+}
+
+func selectedMark(e endpointConfig, selected string) string {
+	if strings.EqualFold(e.Name, selected) {
+		return "*"
+	}
+	return ""
+}
+
+func probeResult(d time.Duration, errText string) string {
+	if errText != "" {
+		return "грешка: " + errText
+	}
+	return "ok " + d.String()
+}
+
+func printEndpointUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s endpoint list
+  %s endpoint probe [--timeout 3s] [--no-switch]
+  %s endpoint use <име>
+
+Endpoint-и су [[endpoint]] табеле у config.toml (name, ssh_host, ssh_port,
+server_url). probe мери SSH порт и Gitea API сваког и прелази на први
+доступан по редоследу из config.toml: мења Host %s блок и endpoint у
+config.toml, па важе његови ssh_host, ssh_port и server_url.
+Са auto_endpoint = true то се ради само пре clone, push и pull.
+`, appName, appName, appName, defaultHostAlias)
+}
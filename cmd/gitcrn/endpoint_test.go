@@ -0,0 +1,165 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const endpointTestConfig = `server_url = "http://100.91.132.35:5000"
+ssh_host = "100.91.132.35"
+ssh_port = 222
+endpoint = "lan"
+auto_endpoint = true
+
+[[endpoint]]
+name = "tailnet"
+ssh_host = "100.91.132.35"
+
+[[endpoint]]
+name = "lan"
+ssh_host = "192.168.1.20"
+ssh_port = 2222
+
+[[endpoint]]
+name = "public"
+ssh_host = "git.example.com"
+server_url = "https://git.example.com/"
+
+[other]
+ssh_host = "ignored"
+`
+
+func writeTestAppConfig(t *testing.T, content string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".config", "gitcrn", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAppConfigEndpoints(t *testing.T) {
+	writeTestAppConfig(t, endpointTestConfig)
+	cfg, err := loadAppConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []endpointConfig{
+		{"tailnet", "100.91.132.35", 222, "http://100.91.132.35:5000"},
+		{"lan", "192.168.1.20", 2222, "http://192.168.1.20:5000"},
+		{"public", "git.example.com", 222, "https://git.example.com"},
+	}
+	if len(cfg.Endpoints) != len(want) {
+		t.Fatalf("endpoints: %+v", cfg.Endpoints)
+	}
+	for i := range want {
+		if cfg.Endpoints[i] != want[i] {
+			t.Fatalf("endpoint %d = %+v, want %+v", i, cfg.Endpoints[i], want[i])
+		}
+	}
+	if !cfg.AutoEndpoint || cfg.SSHHost != "192.168.1.20" || cfg.SSHPort != 2222 || cfg.ServerURL != "http://192.168.1.20:5000" {
+		t.Fatalf("selected endpoint not applied: %+v", cfg)
+	}
+}
+
+func TestPickEndpoint(t *testing.T) {
+	probes := []endpointProbe{
+		{Endpoint: endpointConfig{Name: "tailnet"}, SSHError: "timeout", APIError: "timeout"},
+		{Endpoint: endpointConfig{Name: "lan"}, APIError: "status 502"},
+		{Endpoint: endpointConfig{Name: "public"}, SSHError: "refused"},
+	}
+	if i, ok := pickEndpoint(probes, "ssh"); !ok || i != 1 {
+		t.Fatalf("ssh: %d, %v", i, ok)
+	}
+	if i, ok := pickEndpoint(probes, "https"); !ok || i != 2 {
+		t.Fatalf("https: %d, %v", i, ok)
+	}
+	probes[0].SSHError, probes[0].APIError = "", ""
+	if i, ok := pickEndpoint(probes, "ssh"); !ok || i != 0 {
+		t.Fatalf("fully reachable endpoint should win: %d, %v", i, ok)
+	}
+	if _, ok := pickEndpoint(probes[:0], "ssh"); ok {
+		t.Fatal("no endpoints should not pick one")
+	}
+}
+
+func TestProbeEndpoints(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"1.22.0"}`))
+	}))
+	defer srv.Close()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	probes := probeEndpoints([]endpointConfig{
+		{Name: "down", SSHHost: "127.0.0.1", SSHPort: closedPort, ServerURL: "http://127.0.0.1:" + strconv.Itoa(closedPort)},
+		{Name: "up", SSHHost: "127.0.0.1", SSHPort: port, ServerURL: srv.URL},
+	}, time.Second)
+
+	if probes[0].sshOK() || probes[0].apiOK() {
+		t.Fatalf("closed port should fail: %+v", probes[0])
+	}
+	if !probes[1].sshOK() || !probes[1].apiOK() {
+		t.Fatalf("listening endpoint should pass: %+v", probes[1])
+	}
+	if i, ok := pickEndpoint(probes, "ssh"); !ok || probes[i].Endpoint.Name != "up" {
+		t.Fatalf("picked %d, %v", i, ok)
+	}
+}
+
+func TestSwitchEndpoint(t *testing.T) {
+	path := writeTestAppConfig(t, endpointTestConfig)
+	cfg, err := loadAppConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, _ := cfg.findEndpoint("public")
+	if err := switchEndpoint(cfg, public); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "endpoint = \"public\"\nauto_endpoint = true\n\n[[endpoint]]") || strings.Count(string(data), "[[endpoint]]") != 3 {
+		t.Fatalf("config not updated in place:\n%s", data)
+	}
+	cfg, _ = loadAppConfig()
+	if u, _ := url.Parse(cfg.ServerURL); cfg.SSHHost != "git.example.com" || u.Scheme != "https" {
+		t.Fatalf("effective settings not switched: %+v", cfg)
+	}
+
+	sshConfig, _ := os.ReadFile(filepath.Join(os.Getenv("HOME"), ".ssh", "config"))
+	if !strings.Contains(string(sshConfig), "HostName git.example.com") || !strings.Contains(string(sshConfig), "Port 222") {
+		t.Fatalf("Host block not switched:\n%s", sshConfig)
+	}
+}
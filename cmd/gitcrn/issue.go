@@ -0,0 +1,779 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type giteaLabel struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type giteaMilestone struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+type giteaIssue struct {
+	Number    int64           `json:"number"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	State     string          `json:"state"`
+	HTMLURL   string          `json:"html_url"`
+	User      giteaUser       `json:"user"`
+	Labels    []giteaLabel    `json:"labels"`
+	Milestone *giteaMilestone `json:"milestone"`
+	Assignees []giteaUser     `json:"assignees"`
+	Comments  int             `json:"comments"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type giteaComment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      giteaUser `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// issueJSON is the stable shape printed by --json.
+type issueJSON struct {
+	Number    int64     `json:"number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Author    string    `json:"author"`
+	Labels    []string  `json:"labels"`
+	Assignees []string  `json:"assignees"`
+	Milestone string    `json:"milestone,omitempty"`
+	Comments  int       `json:"comments"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func runIssue(args []string) error {
+	if len(args) < 1 {
+		printIssueUsage(os.Stdout)
+		return nil
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		return runIssueList(args[1:])
+	case "view":
+		return runIssueView(args[1:])
+	case "create", "new":
+		return runIssueCreate(args[1:])
+	case "comment":
+		return runIssueComment(args[1:])
+	case "close":
+		return runIssueSetState(args[1:], "closed")
+	case "reopen":
+		return runIssueSetState(args[1:], "open")
+	case "edit":
+		return runIssueEdit(args[1:])
+	case "-h", "--help", "help":
+		printIssueUsage(os.Stdout)
+		return nil
+	default:
+		printIssueUsage(os.Stderr)
+		return fmt.Errorf("неподржана issue подкоманда: %s", args[0])
+	}
+}
+
+func runIssueList(args []string) error {
+	fs := newFlagSet("issue list")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	state := fs.String("state", "open", "open, closed или all")
+	labels := fs.String("label", "", "Лабеле (зарез)")
+	milestone := fs.String("milestone", "", "Milestone")
+	assignee := fs.String("assignee", "", "Додељени корисник")
+	author := fs.String("author", "", "Аутор")
+	search := fs.String("search", "", "Претрага")
+	limit := fs.Int("limit", 30, "Максималан број резултата")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return issueFlagError(err)
+	}
+	if len(positional) != 0 {
+		printIssueUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(positional, " "))
+	}
+	switch *state {
+	case "open", "closed", "all":
+	default:
+		return fmt.Errorf("--state мора бити open, closed или all")
+	}
+	if *limit <= 0 {
+		return errors.New("--limit мора бити већи од 0")
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("type", "issues")
+	query.Set("state", *state)
+	if v := strings.TrimSpace(*labels); v != "" {
+		query.Set("labels", v)
+	}
+	if v := strings.TrimSpace(*milestone); v != "" {
+		query.Set("milestones", v)
+	}
+	if v := strings.TrimSpace(*assignee); v != "" {
+		query.Set("assigned_by", v)
+	}
+	if v := strings.TrimSpace(*author); v != "" {
+		query.Set("created_by", v)
+	}
+	if v := strings.TrimSpace(*search); v != "" {
+		query.Set("q", v)
+	}
+
+	issues, err := listPages[giteaIssue](client, repoPath(owner, repoName)+"/issues", query, *limit)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		out := make([]issueJSON, 0, len(issues))
+		for _, is := range issues {
+			out = append(out, toIssueJSON(is))
+		}
+		return writeJSON(os.Stdout, out)
+	}
+
+	if len(issues) == 0 {
+		fmt.Fprintln(os.Stderr, "Нема issue-а за задате филтере.")
+		return nil
+	}
+	writeIssueTable(os.Stdout, issues)
+	return nil
+}
+
+// listPages fetches page/limit paginated results until limit items are
+// collected. Issues, pull requests and releases all use this scheme.
+func listPages[T any](client *giteaClient, basePath string, query url.Values, limit int) ([]T, error) {
+	pageSize := 50
+	if limit < pageSize {
+		pageSize = limit
+	}
+
+	var all []T
+	for page := 1; len(all) < limit; page++ {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(page))
+		q.Set("limit", strconv.Itoa(pageSize))
+
+		var batch []T
+		if err := client.doJSON(http.MethodGet, basePath+"?"+q.Encode(), nil, &batch); err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < pageSize {
+			break
+		}
+	}
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	return all, nil
+}
+
+func writeIssueTable(w io.Writer, issues []giteaIssue) {
+	t := newTableWriter(w, "#", "НАСЛОВ", "ЛАБЕЛЕ", "ДОДЕЉЕНО", "АЖУРИРАНО")
+	for _, is := range issues {
+		title := is.Title
+		if is.State == "closed" {
+			title = "[closed] " + title
+		}
+		t.Row(
+			strconv.FormatInt(is.Number, 10),
+			t.Cell(title, 60),
+			strings.Join(labelNames(is.Labels), ","),
+			strings.Join(userLogins(is.Assignees), ","),
+			is.UpdatedAt.Local().Format("2006-01-02"),
+		)
+	}
+	t.Flush()
+}
+
+func runIssueView(args []string) error {
+	fs := newFlagSet("issue view")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	comments := fs.Bool("comments", false, "Прикажи и коментаре")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return issueFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	var is giteaIssue
+	if err := client.doJSON(http.MethodGet, fmt.Sprintf("%s/issues/%d", repoPath(owner, repoName), number), nil, &is); err != nil {
+		return issueNotFound(err, number)
+	}
+
+	var list []giteaComment
+	if *comments {
+		if err := client.doJSON(http.MethodGet, fmt.Sprintf("%s/issues/%d/comments", repoPath(owner, repoName), number), nil, &list); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput() {
+		out := struct {
+			issueJSON
+			Body         string         `json:"body"`
+			CommentsList []giteaComment `json:"comment_list,omitempty"`
+		}{toIssueJSON(is), is.Body, list}
+		return writeJSON(os.Stdout, out)
+	}
+
+	fmt.Println(colorize(fmt.Sprintf("#%d %s", is.Number, is.Title), ansiCyan, stdoutColor))
+	fmt.Printf("Стање: %s · Аутор: %s · Коментари: %d\n", is.State, fallback(is.User.Login, "?"), is.Comments)
+	if names := labelNames(is.Labels); len(names) > 0 {
+		fmt.Printf("Лабеле: %s\n", strings.Join(names, ", "))
+	}
+	if logins := userLogins(is.Assignees); len(logins) > 0 {
+		fmt.Printf("Додељено: %s\n", strings.Join(logins, ", "))
+	}
+	if is.Milestone != nil {
+		fmt.Printf("Milestone: %s\n", is.Milestone.Title)
+	}
+	fmt.Printf("URL: %s\n", is.HTMLURL)
+	if strings.TrimSpace(is.Body) != "" {
+		fmt.Println()
+		fmt.Println(strings.TrimSpace(is.Body))
+	}
+	for _, c := range list {
+		fmt.Println()
+		fmt.Println(colorize(fmt.Sprintf("— %s (%s)", c.User.Login, c.CreatedAt.Local().Format("2006-01-02 15:04")), ansiYellow, stdoutColor))
+		fmt.Println(strings.TrimSpace(c.Body))
+	}
+	return nil
+}
+
+func runIssueCreate(args []string) error {
+	fs := newFlagSet("issue create")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	title := fs.String("title", "", "Наслов")
+	body := fs.String("body", "", "Опис")
+	bodyFile := fs.String("body-file", "", "Опис из фајла или - за stdin")
+	labels := fs.String("label", "", "Лабеле (зарез)")
+	assignees := fs.String("assignee", "", "Додељени корисници (зарез)")
+	milestone := fs.String("milestone", "", "Milestone (наслов)")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return issueFlagError(err)
+	}
+	if len(positional) != 0 {
+		printIssueUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(positional, " "))
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	finalTitle, finalBody, err := resolveTitleBody(*title, *body, *bodyFile, "")
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"title": finalTitle,
+		"body":  finalBody,
+	}
+	if names := splitList(*labels); len(names) > 0 {
+		ids, err := resolveLabelIDs(client, owner, repoName, names)
+		if err != nil {
+			return err
+		}
+		payload["labels"] = ids
+	}
+	if logins := splitList(*assignees); len(logins) > 0 {
+		payload["assignees"] = logins
+	}
+	if strings.TrimSpace(*milestone) != "" {
+		id, err := resolveMilestoneID(client, owner, repoName, *milestone)
+		if err != nil {
+			return err
+		}
+		payload["milestone"] = id
+	}
+
+	var created giteaIssue
+	if err := client.doJSON(http.MethodPost, repoPath(owner, repoName)+"/issues", payload, &created); err != nil {
+		return fmt.Errorf("креирање issue-а није успело: %w", err)
+	}
+
+	fmt.Fprintln(humanOut(), colorize(fmt.Sprintf("Issue #%d креиран: %s", created.Number, created.Title), ansiGreen, stdoutColor))
+	fmt.Fprintln(humanOut(), created.HTMLURL)
+	return nil
+}
+
+func runIssueComment(args []string) error {
+	fs := newFlagSet("issue comment")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	body := fs.String("body", "", "Текст коментара")
+	bodyFile := fs.String("body-file", "", "Коментар из фајла или - за stdin")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return issueFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	text, err := resolveBody(*body, *bodyFile, "")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return errors.New("коментар је празан, прекидам")
+	}
+
+	var c giteaComment
+	endpoint := fmt.Sprintf("%s/issues/%d/comments", repoPath(owner, repoName), number)
+	if err := client.doJSON(http.MethodPost, endpoint, map[string]string{"body": text}, &c); err != nil {
+		return issueNotFound(err, number)
+	}
+	fmt.Fprintln(humanOut(), colorize(fmt.Sprintf("Коментар додат на #%d", number), ansiGreen, stdoutColor))
+	return nil
+}
+
+func runIssueSetState(args []string, state string) error {
+	name := "close"
+	if state == "open" {
+		name = "reopen"
+	}
+	fs := newFlagSet("issue " + name)
+	repoFlag := fs.String("repo", "", "owner/repo")
+	comment := fs.String("comment", "", "Коментар пре промене стања")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return issueFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	base := fmt.Sprintf("%s/issues/%d", repoPath(owner, repoName), number)
+	if strings.TrimSpace(*comment) != "" {
+		if err := client.doJSON(http.MethodPost, base+"/comments", map[string]string{"body": *comment}, nil); err != nil {
+			return issueNotFound(err, number)
+		}
+	}
+
+	var updated giteaIssue
+	if err := client.doJSON(http.MethodPatch, base, map[string]string{"state": state}, &updated); err != nil {
+		return issueNotFound(err, number)
+	}
+
+	msg := fmt.Sprintf("Issue #%d затворен", number)
+	if state == "open" {
+		msg = fmt.Sprintf("Issue #%d поново отворен", number)
+	}
+	fmt.Fprintln(humanOut(), colorize(msg, ansiGreen, stdoutColor))
+	return nil
+}
+
+func runIssueEdit(args []string) error {
+	fs := newFlagSet("issue edit")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	title := fs.String("title", "", "Нови наслов")
+	body := fs.String("body", "", "Нови опис")
+	bodyFile := fs.String("body-file", "", "Нови опис из фајла или - за stdin")
+	addLabels := fs.String("add-label", "", "Додај лабеле (зарез)")
+	removeLabels := fs.String("remove-label", "", "Уклони лабеле (зарез)")
+	assignees := fs.String("assignee", "", "Замени додељене кориснике (зарез)")
+	milestone := fs.String("milestone", "", "Milestone (наслов или ID)")
+	clearMilestone := fs.Bool("clear-milestone", false, "Уклони milestone")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return issueFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+	base := fmt.Sprintf("%s/issues/%d", repoPath(owner, repoName), number)
+
+	payload := map[string]any{}
+	if strings.TrimSpace(*title) != "" {
+		payload["title"] = strings.TrimSpace(*title)
+	}
+	if *body != "" || *bodyFile != "" {
+		text, err := resolveBody(*body, *bodyFile, "")
+		if err != nil {
+			return err
+		}
+		payload["body"] = text
+	}
+	if logins := splitList(*assignees); len(logins) > 0 {
+		payload["assignees"] = logins
+	}
+	if *clearMilestone {
+		payload["milestone"] = 0
+	} else if strings.TrimSpace(*milestone) != "" {
+		id, err := resolveMilestoneID(client, owner, repoName, *milestone)
+		if err != nil {
+			return err
+		}
+		payload["milestone"] = id
+	}
+
+	add := splitList(*addLabels)
+	remove := splitList(*removeLabels)
+	if len(payload) == 0 && len(add) == 0 && len(remove) == 0 {
+		printIssueUsage(os.Stderr)
+		return errors.New("edit тражи бар једну измену")
+	}
+
+	if len(payload) > 0 {
+		if err := client.doJSON(http.MethodPatch, base, payload, nil); err != nil {
+			return issueNotFound(err, number)
+		}
+	}
+	if len(add) > 0 {
+		ids, err := resolveLabelIDs(client, owner, repoName, add)
+		if err != nil {
+			return err
+		}
+		if err := client.doJSON(http.MethodPost, base+"/labels", map[string]any{"labels": ids}, nil); err != nil {
+			return issueNotFound(err, number)
+		}
+	}
+	if len(remove) > 0 {
+		ids, err := resolveLabelIDs(client, owner, repoName, remove)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := client.doJSON(http.MethodDelete, fmt.Sprintf("%s/labels/%d", base, id), nil, nil); err != nil {
+				return issueNotFound(err, number)
+			}
+		}
+	}
+
+	fmt.Fprintln(humanOut(), colorize(fmt.Sprintf("Issue #%d ажуриран", number), ansiGreen, stdoutColor))
+	return nil
+}
+
+func repoClient(repoFlag string) (*giteaClient, string, string, error) {
+	client, err := newGiteaClient()
+	if err != nil {
+		return nil, "", "", err
+	}
+	owner, repoName, err := resolveOwnerRepo(repoFlag, client.serverURL)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return client, owner, repoName, nil
+}
+
+func issueFlagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		printIssueUsage(os.Stdout)
+		return nil
+	}
+	printIssueUsage(os.Stderr)
+	return err
+}
+
+func issueNotFound(err error, number int64) error {
+	if isNotFound(err) {
+		return fmt.Errorf("#%d не постоји", number)
+	}
+	return err
+}
+
+func repoPath(owner, repo string) string {
+	return "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+func singleIssueNumber(positional []string) (int64, error) {
+	if len(positional) != 1 {
+		return 0, errors.New("очекује се тачно један број (нпр 12 или #12)")
+	}
+	return parseIssueNumber(positional[0])
+}
+
+func parseIssueNumber(input string) (int64, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(input), "#")
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("невалидан број: %s", input)
+	}
+	return n, nil
+}
+
+func resolveLabelIDs(client *giteaClient, owner, repo string, names []string) ([]int64, error) {
+	var labels []giteaLabel
+	if err := client.doJSON(http.MethodGet, repoPath(owner, repo)+"/labels?limit=100", nil, &labels); err != nil {
+		return nil, fmt.Errorf("читање лабела: %w", err)
+	}
+
+	byName := map[string]int64{}
+	for _, l := range labels {
+		byName[strings.ToLower(l.Name)] = l.ID
+	}
+
+	ids := make([]int64, 0, len(names))
+	var missing []string
+	for _, n := range names {
+		id, ok := byName[strings.ToLower(n)]
+		if !ok {
+			missing = append(missing, n)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("непознате лабеле: %s", strings.Join(missing, ", "))
+	}
+	return ids, nil
+}
+
+func resolveMilestoneID(client *giteaClient, owner, repo, title string) (int64, error) {
+	want := strings.TrimSpace(title)
+	if n, err := strconv.ParseInt(want, 10, 64); err == nil && n > 0 {
+		return n, nil
+	}
+
+	var milestones []giteaMilestone
+	if err := client.doJSON(http.MethodGet, repoPath(owner, repo)+"/milestones?state=all&limit=100", nil, &milestones); err != nil {
+		return 0, fmt.Errorf("читање milestone-а: %w", err)
+	}
+	for _, m := range milestones {
+		if strings.EqualFold(m.Title, want) {
+			return m.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("непознат milestone: %s", want)
+}
+
+// resolveTitleBody fills title/body from flags, a file, stdin or $EDITOR.
+// In the editor the first line is the title and the rest is the body.
+func resolveTitleBody(title, body, bodyFile, template string) (string, string, error) {
+	title = strings.TrimSpace(title)
+	if title != "" {
+		text, err := resolveBody(body, bodyFile, template)
+		return title, text, err
+	}
+	if body != "" || bodyFile != "" {
+		return "", "", errors.New("--title је обавезан уз --body/--body-file")
+	}
+	if !isTerminal(os.Stdin) {
+		return "", "", errors.New("--title је обавезан када stdin није терминал")
+	}
+
+	edited, err := editText(template)
+	if err != nil {
+		return "", "", err
+	}
+	first, rest, _ := strings.Cut(strings.TrimLeft(edited, "\n"), "\n")
+	first = strings.TrimSpace(first)
+	if first == "" {
+		return "", "", errors.New("наслов је празан, прекидам")
+	}
+	return first, strings.TrimSpace(rest), nil
+}
+
+func resolveBody(body, bodyFile, template string) (string, error) {
+	if body != "" && bodyFile != "" {
+		return "", errors.New("користи или --body или --body-file, не оба")
+	}
+	if body != "" {
+		return body, nil
+	}
+	if bodyFile == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("читање stdin-а: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if bodyFile != "" {
+		data, err := os.ReadFile(bodyFile)
+		if err != nil {
+			return "", fmt.Errorf("читање %s: %w", bodyFile, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if !isTerminal(os.Stdin) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("читање stdin-а: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	text, err := editText(template)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text), nil
+}
+
+func editText(initial string) (string, error) {
+	f, err := os.CreateTemp("", "gitcrn-*.md")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command("sh", "-c", editor+" "+shellSingleQuote(path))
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", editor+" "+path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s није успео: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return normalizeNewlines(string(data)), nil
+}
+
+func editorCommand() string {
+	for _, env := range []string{"GITCRN_EDITOR", "VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+	if v := strings.TrimSpace(commandOutput("git", "var", "GIT_EDITOR")); v != "" {
+		return v
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+func toIssueJSON(is giteaIssue) issueJSON {
+	out := issueJSON{
+		Number:    is.Number,
+		Title:     is.Title,
+		State:     is.State,
+		Author:    is.User.Login,
+		Labels:    labelNames(is.Labels),
+		Assignees: userLogins(is.Assignees),
+		Comments:  is.Comments,
+		URL:       is.HTMLURL,
+		CreatedAt: is.CreatedAt,
+		UpdatedAt: is.UpdatedAt,
+	}
+	if is.Milestone != nil {
+		out.Milestone = is.Milestone.Title
+	}
+	return out
+}
+
+func labelNames(labels []giteaLabel) []string {
+	out := make([]string, 0, len(labels))
+	for _, l := range labels {
+		out = append(out, l.Name)
+	}
+	return out
+}
+
+func userLogins(users []giteaUser) []string {
+	out := make([]string, 0, len(users))
+	for _, u := range users {
+		out = append(out, u.Login)
+	}
+	return out
+}
+
+func splitList(input string) []string {
+	var out []string
+	for _, item := range strings.Split(input, ",") {
+		if v := strings.TrimSpace(item); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func printIssueUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s issue list [--state open|closed|all] [--label a,b] [--milestone m] [--assignee u] [--author u] [--search q] [--limit 30] [--json]
+  %s issue view <број> [--comments] [--json]
+  %s issue create [--title "..."] [--body "..." | --body-file <file|->] [--label a,b] [--assignee u] [--milestone m]
+  %s issue comment <број> [--body "..." | --body-file <file|->]
+  %s issue close <број> [--comment "..."]
+  %s issue reopen <број> [--comment "..."]
+  %s issue edit <број> [--title ...] [--body ...] [--add-label a] [--remove-label b] [--assignee u] [--milestone m | --clear-milestone]
+
+Све подкоманде примају --repo owner/repo. Без њега repo се чита из
+gitcrn (па origin) remote-а тренутног репоа.
+
+Без --title/--body отвара се $EDITOR (прва линија је наслов). Ако stdin
+није терминал, опис се чита са stdin-а.
+`, appName, appName, appName, appName, appName, appName, appName)
+}
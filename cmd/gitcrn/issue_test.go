@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseIssueNumber(t *testing.T) {
+	for _, in := range []string{"12", "#12", " 12 "} {
+		n, err := parseIssueNumber(in)
+		if err != nil || n != 12 {
+			t.Fatalf("%q: got %d, %v", in, n, err)
+		}
+	}
+	for _, in := range []string{"", "abc", "0", "-3"} {
+		if _, err := parseIssueNumber(in); err == nil {
+			t.Fatalf("%q: expected error", in)
+		}
+	}
+}
+
+func TestResolveLabelIDs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/vltc/kapri/labels" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[{"id":1,"name":"bug"},{"id":7,"name":"Feature"}]`)
+	}))
+	defer srv.Close()
+
+	client := &giteaClient{serverURL: srv.URL, token: "t"}
+	ids, err := resolveLabelIDs(client, "vltc", "kapri", []string{"feature", "bug"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 7 || ids[1] != 1 {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+
+	_, err = resolveLabelIDs(client, "vltc", "kapri", []string{"bug", "nope"})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected unknown label error, got %v", err)
+	}
+}
+
+func TestListPagesStopsAtLimit(t *testing.T) {
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		items := make([]giteaIssue, 0, size)
+		for i := 0; i < size; i++ {
+			items = append(items, giteaIssue{Number: int64((page-1)*size + i + 1)})
+		}
+		json.NewEncoder(w).Encode(items)
+	}))
+	defer srv.Close()
+
+	client := &giteaClient{serverURL: srv.URL, token: "t"}
+	q := url.Values{}
+	q.Set("state", "open")
+	issues, err := listPages[giteaIssue](client, "repos/vltc/kapri/issues", q, 70)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 70 || issues[69].Number != 70 {
+		t.Fatalf("unexpected issues: %d", len(issues))
+	}
+	if len(queries) != 2 || queries[0].Get("state") != "open" {
+		t.Fatalf("unexpected queries: %v", queries)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -27,10 +28,10 @@ const (
 	defaultServerURL = "http://100.91.132.35:5000"
 	projectURL       = "https://github.com/crnobog69/gitcrn-cli-bin"
 	creatorNames     = "crnijada / crnobog / vltc"
-	latestReleaseAPI = "https://api.github.com/repos/crnobog69/gitcrn-cli-bin/releases/latest"
 	updateLinuxCmd   = "curl -fsSL https://raw.githubusercontent.com/crnobog69/gitcrn-cli-bin/refs/heads/master/scripts/update.sh | bash"
 	updateWinCmd     = "iwr https://raw.githubusercontent.com/crnobog69/gitcrn-cli-bin/refs/heads/master/scripts/update.ps1 -UseBasicParsing | iex"
 	defaultCommitMsg = "❄️"
+	defaultProtocol  = "ssh"
 
 	ansiReset  = "\033[0m"
 	ansiRed    = "\033[31m"
@@ -53,12 +54,49 @@ type appConfig struct {
 	SSHHost   string
 	SSHPort   int
 	SSHUser   string
+	Protocol  string
+
+	// TailscaleNode is the machine name ssh_host was resolved from by
+	// init --tailscale; doctor uses it to notice a changed address.
+	TailscaleNode string
+
+	// Endpoints are alternative addresses of the same server ([[endpoint]]
+	// tables). When Endpoint names one of them, its host, port and API URL
+	// replace ssh_host, ssh_port and server_url.
+	Endpoints    []endpointConfig
+	Endpoint     string
+	AutoEndpoint bool
+
+	UpdateBaseURL       string
+	UpdateCheckInterval time.Duration
+	UpdateChannel       string
+	UpdateProvider      string
+	UpdateOwner         string
+	UpdateRepo          string
 }
 
 type giteaUser struct {
 	Login string `json:"login"`
 }
 
+type giteaRepo struct {
+	FullName      string    `json:"full_name"`
+	Description   string    `json:"description"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	Archived      bool      `json:"archived"`
+	Empty         bool      `json:"empty"`
+	DefaultBranch string    `json:"default_branch"`
+	HTMLURL       string    `json:"html_url"`
+	SSHURL        string    `json:"ssh_url"`
+	CloneURL      string    `json:"clone_url"`
+	Stars         int       `json:"stars_count"`
+	Forks         int       `json:"forks_count"`
+	OpenIssues    int       `json:"open_issues_count"`
+	OpenPulls     int       `json:"open_pr_counter"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
 type giteaCreateRepoRequest struct {
 	Name          string `json:"name"`
 	Description   string `json:"description,omitempty"`
@@ -75,16 +113,35 @@ func main() {
 		os.Exit(1)
 	}
 
-	cmd := os.Args[1]
-	args := os.Args[2:]
+	rawArgs, format, err := parseGlobalOutputFlags(os.Args[1:])
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+	outputFormat = format
+	if machineOutput() {
+		stdoutColor = false
+	}
+	if len(rawArgs) == 0 {
+		printRootUsage(os.Stderr)
+		os.Exit(1)
+	}
+
+	cmd := rawArgs[0]
+	args := rawArgs[1:]
 
-	if shouldCheckUpdates(cmd) {
+	if shouldCheckUpdates(cmd) && !mentionsMachineOutput(args) {
 		maybePrintUpdateNotice()
 	}
 
 	switch cmd {
+	case updateCheckCommand:
+		runUpdateCheckRefresh()
 	case "-v", "--version", "version":
-		printVersion(os.Stdout)
+		if err := runVersion(os.Stdout, args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
 	case "completion":
 		if err := runCompletion(args); err != nil {
 			printError(err)
@@ -96,7 +153,12 @@ func main() {
 			os.Exit(1)
 		}
 	case "-pp":
-		if err := runMake([]string{"--push", "--pull"}, false); err != nil {
+		if err := runMake(append([]string{"--push", "--pull"}, args...), false); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "endpoint":
+		if err := runEndpoint(args); err != nil {
 			printError(err)
 			os.Exit(1)
 		}
@@ -120,6 +182,11 @@ func main() {
 			printError(err)
 			os.Exit(1)
 		}
+	case "uninit":
+		if err := runUninit(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
 	case "generate":
 		if err := runGenerate(args); err != nil {
 			printError(err)
@@ -135,6 +202,41 @@ func main() {
 			printError(err)
 			os.Exit(1)
 		}
+	case "config":
+		if err := runConfig(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "api":
+		if err := runAPI(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "issue":
+		if err := runIssue(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "pr":
+		if err := runPR(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "release":
+		if err := runRelease(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "self-update":
+		if err := runSelfUpdate(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "browse":
+		if err := runBrowse(args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
 	case "clone":
 		if err := runClone(args); err != nil {
 			printError(err)
@@ -171,15 +273,38 @@ func main() {
 	}
 }
 
+// runVersion also parses its arguments, so `gitcrn -v --json` works: the
+// global scan stops at -v like at any other flag.
+func runVersion(w io.Writer, args []string) error {
+	fs := newFlagSet("version")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printVersion(w)
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+	if jsonOutput() {
+		return writeJSON(w, currentVersionInfo())
+	}
+	printVersion(w)
+	return nil
+}
+
 func runInit(args []string) error {
-	fs := flag.NewFlagSet("init", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
+	fs := newFlagSet("init")
 
 	defaultMode := fs.Bool("default", false, "Користи подразумевана подешавања")
 	customMode := fs.Bool("custom", false, "Користи прилагођена подешавања")
 	host := fs.String("host", "", "SSH HostName")
 	port := fs.Int("port", 0, "SSH Port")
 	user := fs.String("user", "", "SSH User")
+	machine := fs.String("tailscale", "", "Tailscale име сервера")
+	magicDNS := fs.Bool("magicdns", false, "Уз --tailscale упиши MagicDNS име уместо IP-а")
+	dryRun := fs.Bool("dry-run", false, "Само прикажи diff, ништа не мењај")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -190,9 +315,20 @@ func runInit(args []string) error {
 		return err
 	}
 
-	if *defaultMode == *customMode {
+	tailscaleMode := strings.TrimSpace(*machine) != ""
+	modes := 0
+	for _, on := range []bool{*defaultMode, *customMode, tailscaleMode} {
+		if on {
+			modes++
+		}
+	}
+	if modes != 1 {
 		printInitUsage(os.Stderr)
-		return errors.New("изабери тачно један режим: --default или --custom")
+		return errors.New("изабери тачно један режим: --default, --custom или --tailscale <машина>")
+	}
+	if *magicDNS && !tailscaleMode {
+		printInitUsage(os.Stderr)
+		return errors.New("--magicdns иде само уз --tailscale")
 	}
 
 	if fs.NArg() != 0 {
@@ -200,8 +336,14 @@ func runInit(args []string) error {
 		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
 	}
 
-	if err := ensureTailscaleAvailable(); err != nil {
-		return err
+	if !*dryRun {
+		if err := ensureTailscaleAvailable(); err != nil {
+			return err
+		}
+	}
+
+	if tailscaleMode {
+		return initFromTailscale(strings.TrimSpace(*machine), *magicDNS, strings.TrimSpace(*user), *port, *dryRun)
 	}
 
 	finalHost := defaultHostName
@@ -224,153 +366,128 @@ func runInit(args []string) error {
 		finalUser = strings.TrimSpace(*user)
 	}
 
-	configPath, changed, err := upsertSSHConfig(defaultHostAlias, finalHost, finalUser, finalPort)
+	change, err := planSSHConfigChange(defaultHostAlias, finalHost, finalUser, finalPort)
 	if err != nil {
 		return err
 	}
-
-	if !changed {
-		fmt.Println(colorize("SSH конфигурација већ постоји: "+configPath, ansiYellow, stdoutColor))
-		fmt.Printf("Host %s -> %s:%d као %s\n", defaultHostAlias, finalHost, finalPort, finalUser)
+	if *dryRun {
+		printConfigDiff(os.Stdout, change.Path, change.Old, change.New)
 		return nil
 	}
 
-	fmt.Println(colorize("SSH конфигурација ажурирана: "+configPath, ansiGreen, stdoutColor))
-	fmt.Printf("Host %s -> %s:%d као %s\n", defaultHostAlias, finalHost, finalPort, finalUser)
-	return nil
-}
+	// The SSH config can be written offline or before tailscale up; the
+	// tailnet only matters once something connects.
+	if err := ensureTailnetUp(finalHost); err != nil {
+		fmt.Fprintln(os.Stderr, colorize("Упозорење: "+err.Error(), ansiYellow, stderrColor))
+	} else {
+		printTailscalePeerStatus(finalHost)
+	}
 
-func runDoctor(args []string) error {
-	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
+	if !change.changed() {
+		fmt.Println(colorize("SSH конфигурација већ постоји: "+change.Path, ansiYellow, stdoutColor))
+		fmt.Printf("Host %s -> %s:%d као %s\n", defaultHostAlias, finalHost, finalPort, finalUser)
+		return nil
+	}
 
-	if err := fs.Parse(args); err != nil {
-		if errors.Is(err, flag.ErrHelp) {
-			printDoctorUsage(os.Stdout)
-			return nil
-		}
-		printDoctorUsage(os.Stderr)
+	backup, err := change.apply()
+	if err != nil {
 		return err
 	}
-
-	if fs.NArg() != 0 {
-		printDoctorUsage(os.Stderr)
-		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	fmt.Println(colorize("SSH конфигурација ажурирана: "+change.Path, ansiGreen, stdoutColor))
+	if backup != "" {
+		fmt.Println("Резервна копија: " + backup)
 	}
-
-	fmt.Println(colorize("Провера окружења (doctor)", ansiCyan, stdoutColor))
-	doctorCheckTailscale()
-	doctorCheckGitIdentity()
-	doctorCheckSSHConfig()
+	fmt.Printf("Host %s -> %s:%d као %s\n", defaultHostAlias, finalHost, finalPort, finalUser)
 	return nil
 }
 
-func doctorCheckTailscale() {
-	out, err := exec.Command("tailscale", "version").CombinedOutput()
-	if err != nil {
-		doctorWarn("Tailscale", "није инсталиран или није у PATH-у")
-		fmt.Printf("  Инсталација: https://tailscale.com/download\n")
-		return
-	}
-
-	firstLine := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
-	if firstLine == "" {
-		firstLine = "доступан"
-	}
-	doctorOK("Tailscale", firstLine)
+// printConfigDiff shows what init would change in path, or that nothing
+// would change.
+func printConfigDiff(w io.Writer, path, oldContent, newContent string) {
+	printFileDiff(w, path, path+" ("+appName+" init)", oldContent, newContent)
 }
 
-func doctorCheckGitIdentity() {
-	if out, err := exec.Command("git", "--version").CombinedOutput(); err != nil {
-		doctorWarn("Git", "није инсталиран или није у PATH-у")
+func printFileDiff(w io.Writer, oldName, newName, oldContent, newContent string) {
+	diff := unifiedDiff(oldName, newName, oldContent, newContent)
+	if diff == "" {
+		fmt.Fprintln(w, "Без промена: "+oldName)
 		return
-	} else {
-		line := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
-		if line == "" {
-			line = "доступан"
+	}
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Fprint(w, line)
+		case strings.HasPrefix(line, "+"):
+			fmt.Fprint(w, colorize(strings.TrimSuffix(line, "\n"), ansiGreen, stdoutColor)+"\n")
+		case strings.HasPrefix(line, "-"):
+			fmt.Fprint(w, colorize(strings.TrimSuffix(line, "\n"), ansiRed, stdoutColor)+"\n")
+		case strings.HasPrefix(line, "@@"):
+			fmt.Fprint(w, colorize(strings.TrimSuffix(line, "\n"), ansiCyan, stdoutColor)+"\n")
+		default:
+			fmt.Fprint(w, line)
 		}
-		doctorOK("Git", line)
 	}
+}
 
-	nameGlobal := strings.TrimSpace(commandOutput("git", "config", "--global", "--get", "user.name"))
-	emailGlobal := strings.TrimSpace(commandOutput("git", "config", "--global", "--get", "user.email"))
-	nameLocal := strings.TrimSpace(commandOutput("git", "config", "--get", "user.name"))
-	emailLocal := strings.TrimSpace(commandOutput("git", "config", "--get", "user.email"))
-
-	if nameLocal != "" || emailLocal != "" {
-		doctorOK("Git идентитет (локални)", fmt.Sprintf("%s <%s>", fallback(nameLocal, "?"), fallback(emailLocal, "?")))
-	} else {
-		doctorWarn("Git идентитет (локални)", "није подешен у тренутном репозиторијуму")
+// initFromTailscale resolves the server by its Tailscale machine name, so a
+// re-IP'd node needs only another init instead of editing configs by hand.
+func initFromTailscale(machine string, magicDNS bool, user string, port int, dryRun bool) error {
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return err
 	}
-
-	if nameGlobal != "" || emailGlobal != "" {
-		doctorOK("Git идентитет (глобални)", fmt.Sprintf("%s <%s>", fallback(nameGlobal, "?"), fallback(emailGlobal, "?")))
-	} else {
-		doctorWarn("Git идентитет (глобални)", "постави: git config --global user.name \"Твоје Име\" && git config --global user.email \"ти@мејл\"")
+	if user == "" {
+		user = cfg.SSHUser
+	}
+	if port == 0 {
+		port = cfg.SSHPort
+	}
+	if port < 1 || port > 65535 {
+		return errors.New("--port мора бити између 1 и 65535")
 	}
-}
 
-func doctorCheckSSHConfig() {
-	configPath, err := sshConfigPath()
+	st, err := readTailscaleStatus()
 	if err != nil {
-		doctorWarn("SSH конфиг", err.Error())
-		return
+		return err
 	}
-
-	data, err := os.ReadFile(configPath)
+	host, peer, err := resolveTailscaleMachine(st, machine, magicDNS)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			doctorWarn("SSH конфиг", fmt.Sprintf("не постоји (%s). Покрени: gitcrn init --default", configPath))
-			return
-		}
-		doctorWarn("SSH конфиг", fmt.Sprintf("грешка читања %s: %v", configPath, err))
-		return
+		return err
 	}
-
-	content := normalizeNewlines(string(data))
-	settings, found := findSSHHostSettings(content, defaultHostAlias)
-	if !found {
-		doctorWarn("SSH host gitcrn", "није пронађен у ~/.ssh/config. Покрени: gitcrn init --default")
-		return
+	if err := tailnetError(st, host); err != nil {
+		return err
 	}
+	fmt.Printf("Tailscale: %s -> %s (%s)\n", machine, host, peer.connection())
 
-	hostName := fallback(settings["hostname"], "?")
-	user := fallback(settings["user"], "?")
-	port := fallback(settings["port"], "?")
-	doctorOK("SSH host gitcrn", fmt.Sprintf("HostName=%s User=%s Port=%s", hostName, user, port))
-
-	identity := strings.TrimSpace(settings["identityfile"])
-	pubPath := ""
-	if identity != "" {
-		pubPath = identityPublicKeyPath(identity)
-	}
-	if pubPath == "" {
-		pubPath = firstExistingPath(defaultPublicKeyCandidates()...)
-	}
+	if dryRun {
+		change, err := planSSHConfigChange(defaultHostAlias, host, user, port)
+		if err != nil {
+			return err
+		}
+		printConfigDiff(os.Stdout, change.Path, change.Old, change.New)
 
-	if pubPath == "" {
-		doctorWarn("SSH кључ", "ниједан .pub кључ није пронађен у ~/.ssh")
-		return
+		configPath, err := appConfigPath()
+		if err != nil {
+			return err
+		}
+		oldConfig, newConfig, err := planAppConfigChange(configPath, tailscaleConfigValues(cfg, machine, host, user, port))
+		if err != nil {
+			return err
+		}
+		printConfigDiff(os.Stdout, configPath, oldConfig, newConfig)
+		return nil
 	}
 
-	keyType, comment, err := readPublicKeyInfo(pubPath)
+	sshPath, serverURL, err := applyTailscaleHost(cfg, machine, host, user, port)
 	if err != nil {
-		doctorWarn("SSH кључ", fmt.Sprintf("%s (не могу да прочитам коментар: %v)", pubPath, err))
-		return
-	}
-
-	if comment == "" {
-		comment = "(без коментара)"
+		return err
 	}
-	doctorOK("SSH кључ", fmt.Sprintf("%s [%s] коментар: %s", pubPath, keyType, comment))
-}
-
-func doctorOK(name, details string) {
-	fmt.Printf("%s %s: %s\n", colorize("[OK]", ansiGreen, stdoutColor), name, details)
-}
-
-func doctorWarn(name, details string) {
-	fmt.Printf("%s %s: %s\n", colorize("[WARN]", ansiYellow, stdoutColor), name, details)
+	configPath, _ := appConfigPath()
+	fmt.Println(colorize("SSH конфигурација ажурирана: "+sshPath, ansiGreen, stdoutColor))
+	fmt.Printf("Host %s -> %s:%d као %s\n", defaultHostAlias, host, port, user)
+	fmt.Println(colorize("Config ажуриран: "+configPath, ansiGreen, stdoutColor))
+	fmt.Printf("server_url = %s\n", serverURL)
+	return nil
 }
 
 func runGenerate(args []string) error {
@@ -392,8 +509,7 @@ func runGenerate(args []string) error {
 }
 
 func runGenerateConfig(args []string) error {
-	fs := flag.NewFlagSet("generate config", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
+	fs := newFlagSet("generate config")
 	force := fs.Bool("force", false, "Препиши постојећи config.toml")
 
 	if err := fs.Parse(args); err != nil {
@@ -414,30 +530,14 @@ func runGenerateConfig(args []string) error {
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
-		return fmt.Errorf("креирање config директоријума: %w", err)
-	}
-
 	if !*force {
 		if _, err := os.Stat(configPath); err == nil {
 			return fmt.Errorf("%s већ постоји. Користи --force ако желиш препис", configPath)
 		}
 	}
 
-	content := strings.Join([]string{
-		"# gitcrn config",
-		fmt.Sprintf("server_url = %q", defaultServerURL),
-		"token = \"\"",
-		"",
-		fmt.Sprintf("ssh_alias = %q", defaultHostAlias),
-		fmt.Sprintf("ssh_host = %q", defaultHostName),
-		fmt.Sprintf("ssh_port = %d", defaultHostPort),
-		fmt.Sprintf("ssh_user = %q", defaultHostUser),
-		"",
-	}, "\n")
-
-	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
-		return fmt.Errorf("упис %s: %w", configPath, err)
+	if err := writeDefaultAppConfig(configPath); err != nil {
+		return err
 	}
 
 	fmt.Println(colorize("Креиран config: "+configPath, ansiGreen, stdoutColor))
@@ -472,6 +572,10 @@ func runRepo(args []string) error {
 	switch args[0] {
 	case "create":
 		return runCreateRepo(args[1:])
+	case "view":
+		return runRepoView(args[1:])
+	case "list", "ls":
+		return runRepoList(args[1:])
 	case "-h", "--help", "help":
 		printRepoUsage(os.Stdout)
 		return nil
@@ -481,9 +585,169 @@ func runRepo(args []string) error {
 	}
 }
 
+func runRepoView(args []string) error {
+	if len(args) > 1 {
+		printRepoUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(args[1:], " "))
+	}
+	if len(args) == 1 && (args[0] == "-h" || args[0] == "--help") {
+		printRepoUsage(os.Stdout)
+		return nil
+	}
+
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return err
+	}
+	serverURL := resolveServerURL(cfg)
+
+	arg := ""
+	if len(args) == 1 {
+		arg = args[0]
+	}
+	owner, repoName, err := resolveOwnerRepo(arg, serverURL)
+	if err != nil {
+		return err
+	}
+
+	r, err := giteaGetRepo(serverURL, resolveToken(cfg), owner, repoName)
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		return writeJSON(os.Stdout, toRepoJSON(r))
+	}
+
+	visibility := "public"
+	if r.Private {
+		visibility = "private"
+	}
+	fmt.Println(colorize(fallback(r.FullName, owner+"/"+repoName), ansiCyan, stdoutColor))
+	if strings.TrimSpace(r.Description) != "" {
+		fmt.Println(r.Description)
+	}
+	fmt.Printf("Видљивост: %s\n", visibility)
+	fmt.Printf("Подразумевана грана: %s\n", fallback(r.DefaultBranch, "?"))
+	fmt.Printf("Web: %s\n", fallback(r.HTMLURL, "?"))
+	fmt.Printf("SSH: %s\n", fallback(r.SSHURL, "?"))
+	fmt.Printf("HTTPS: %s\n", fallback(r.CloneURL, "?"))
+	fmt.Printf("Звездице/Forks: %d/%d\n", r.Stars, r.Forks)
+	fmt.Printf("Отворени issues: %d\n", r.OpenIssues)
+	fmt.Printf("Отворени PR-ови: %d\n", r.OpenPulls)
+	if r.Archived {
+		fmt.Println(colorize("Архивиран", ansiYellow, stdoutColor))
+	}
+	if r.Empty {
+		fmt.Println(colorize("Празан репозиторијум", ansiYellow, stdoutColor))
+	}
+	if !r.UpdatedAt.IsZero() {
+		fmt.Printf("Ажуриран: %s\n", r.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+type repoJSON struct {
+	FullName      string    `json:"full_name"`
+	Description   string    `json:"description"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	Archived      bool      `json:"archived"`
+	Empty         bool      `json:"empty"`
+	DefaultBranch string    `json:"default_branch"`
+	HTMLURL       string    `json:"html_url"`
+	SSHURL        string    `json:"ssh_url"`
+	CloneURL      string    `json:"clone_url"`
+	Stars         int       `json:"stars"`
+	Forks         int       `json:"forks"`
+	OpenIssues    int       `json:"open_issues"`
+	OpenPulls     int       `json:"open_pulls"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func toRepoJSON(r giteaRepo) repoJSON {
+	return repoJSON{
+		FullName:      r.FullName,
+		Description:   r.Description,
+		Private:       r.Private,
+		Fork:          r.Fork,
+		Archived:      r.Archived,
+		Empty:         r.Empty,
+		DefaultBranch: r.DefaultBranch,
+		HTMLURL:       r.HTMLURL,
+		SSHURL:        r.SSHURL,
+		CloneURL:      r.CloneURL,
+		Stars:         r.Stars,
+		Forks:         r.Forks,
+		OpenIssues:    r.OpenIssues,
+		OpenPulls:     r.OpenPulls,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
+
+func runRepoList(args []string) error {
+	fs := newFlagSet("repo list")
+	limit := fs.Int("limit", 50, "Максималан број резултата")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printRepoUsage(os.Stdout)
+			return nil
+		}
+		printRepoUsage(os.Stderr)
+		return err
+	}
+	if len(positional) > 1 {
+		printRepoUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(positional[1:], " "))
+	}
+	if *limit <= 0 {
+		return errors.New("--limit мора бити већи од 0")
+	}
+
+	client, err := newGiteaClient()
+	if err != nil {
+		return err
+	}
+
+	path := "user/repos"
+	if len(positional) == 1 {
+		path = "users/" + url.PathEscape(strings.Trim(positional[0], "/")) + "/repos"
+	}
+	repos, err := listPages[giteaRepo](client, path, url.Values{}, *limit)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		out := make([]repoJSON, 0, len(repos))
+		for _, r := range repos {
+			out = append(out, toRepoJSON(r))
+		}
+		return writeJSON(os.Stdout, out)
+	}
+
+	if len(repos) == 0 {
+		fmt.Fprintln(os.Stderr, "Нема репозиторијума.")
+		return nil
+	}
+	t := newTableWriter(os.Stdout, "РЕПО", "ВИДЉИВОСТ", "ОПИС", "АЖУРИРАНО")
+	for _, r := range repos {
+		visibility := "public"
+		if r.Private {
+			visibility = "private"
+		}
+		if r.Archived {
+			visibility += ",archived"
+		}
+		t.Row(r.FullName, visibility, t.Cell(r.Description, 50), r.UpdatedAt.Local().Format("2006-01-02"))
+	}
+	t.Flush()
+	return nil
+}
+
 func runCreateRepo(args []string) error {
-	fs := flag.NewFlagSet("create repo", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
+	fs := newFlagSet("create repo")
 
 	private := fs.Bool("private", true, "Креирај private репозиторијум")
 	public := fs.Bool("public", false, "Креирај public репозиторијум")
@@ -516,22 +780,11 @@ func runCreateRepo(args []string) error {
 	if err != nil {
 		return err
 	}
-	token := strings.TrimSpace(os.Getenv("GITCRN_TOKEN"))
+	token := resolveToken(cfg)
 	if token == "" {
-		token = strings.TrimSpace(os.Getenv("GITEA_TOKEN"))
+		return errMissingToken
 	}
-	if token == "" {
-		token = strings.TrimSpace(cfg.Token)
-	}
-	if token == "" {
-		return errors.New("недостаје token. Постави GITCRN_TOKEN или token у ~/.config/gitcrn/config.toml")
-	}
-
-	serverURL := strings.TrimSpace(cfg.ServerURL)
-	if serverURL == "" {
-		serverURL = defaultServerURL
-	}
-	serverURL = strings.TrimRight(serverURL, "/")
+	serverURL := resolveServerURL(cfg)
 
 	login, err := giteaCurrentUser(serverURL, token)
 	if err != nil {
@@ -550,21 +803,49 @@ func runCreateRepo(args []string) error {
 		DefaultBranch: strings.TrimSpace(*defaultBranch),
 	}
 
-	if err := giteaCreateRepo(endpoint, token, payload); err != nil {
+	created, err := giteaCreateRepo(endpoint, token, payload)
+	if err != nil {
 		return err
 	}
 
 	ownerRepo := fmt.Sprintf("%s/%s", owner, repoName)
-	fmt.Println(colorize("Репозиторијум креиран: "+owner+"/"+repoName, ansiGreen, stdoutColor))
+	if jsonOutput() {
+		if err := writeJSON(os.Stdout, toRepoJSON(created)); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(humanOut(), colorize("Репозиторијум креиран: "+owner+"/"+repoName, ansiGreen, stdoutColor))
+	}
 
 	if *cloneNow {
 		return runClone([]string{ownerRepo})
 	}
-	fmt.Printf("Следеће: %s clone %s\n", appName, ownerRepo)
-	fmt.Printf("У постојећем репоу: %s add %s\n", appName, ownerRepo)
+	fmt.Fprintf(humanOut(), "Следеће: %s clone %s\n", appName, ownerRepo)
+	fmt.Fprintf(humanOut(), "У постојећем репоу: %s add %s\n", appName, ownerRepo)
 	return nil
 }
 
+var errMissingToken = errors.New("недостаје token. Постави GITCRN_TOKEN или token у ~/.config/gitcrn/config.toml")
+
+func resolveToken(cfg appConfig) string {
+	token := strings.TrimSpace(os.Getenv("GITCRN_TOKEN"))
+	if token == "" {
+		token = strings.TrimSpace(os.Getenv("GITEA_TOKEN"))
+	}
+	if token == "" {
+		token = strings.TrimSpace(cfg.Token)
+	}
+	return token
+}
+
+func resolveServerURL(cfg appConfig) string {
+	serverURL := strings.TrimSpace(cfg.ServerURL)
+	if serverURL == "" {
+		serverURL = defaultServerURL
+	}
+	return strings.TrimRight(serverURL, "/")
+}
+
 func parseOwnerRepo(input string) (owner, repo string, err error) {
 	s := strings.TrimSpace(input)
 	s = strings.TrimSuffix(strings.TrimPrefix(s, "/"), ".git")
@@ -608,10 +889,10 @@ func giteaCurrentUser(serverURL, token string) (string, error) {
 	return strings.TrimSpace(u.Login), nil
 }
 
-func giteaCreateRepo(endpoint, token string, payload giteaCreateRepoRequest) error {
+func giteaCreateRepo(endpoint, token string, payload giteaCreateRepoRequest) (giteaRepo, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return giteaRepo{}, err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -619,7 +900,7 @@ func giteaCreateRepo(endpoint, token string, payload giteaCreateRepoRequest) err
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(string(body)))
 	if err != nil {
-		return err
+		return giteaRepo{}, err
 	}
 	req.Header.Set("Authorization", "token "+token)
 	req.Header.Set("Accept", "application/json")
@@ -628,15 +909,27 @@ func giteaCreateRepo(endpoint, token string, payload giteaCreateRepoRequest) err
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return giteaRepo{}, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return nil
+		var r giteaRepo
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			return giteaRepo{}, err
+		}
+		return r, nil
 	}
 
-	msg, _ := io.ReadAll(resp.Body)
+	trimmed := giteaErrorMessage(resp.Body)
+	if resp.StatusCode == http.StatusConflict {
+		return giteaRepo{}, fmt.Errorf("repo већ постоји: %s", trimmed)
+	}
+	return giteaRepo{}, fmt.Errorf("create repo неуспешан (status %d): %s", resp.StatusCode, trimmed)
+}
+
+func giteaErrorMessage(body io.Reader) string {
+	msg, _ := io.ReadAll(body)
 	trimmed := strings.TrimSpace(string(msg))
 	var errPayload map[string]any
 	if err := json.Unmarshal(msg, &errPayload); err == nil {
@@ -647,10 +940,98 @@ func giteaCreateRepo(endpoint, token string, payload giteaCreateRepoRequest) err
 	if trimmed == "" {
 		trimmed = "непозната грешка"
 	}
-	if resp.StatusCode == http.StatusConflict {
-		return fmt.Errorf("repo већ постоји: %s", trimmed)
+	return trimmed
+}
+
+func giteaGetRepo(serverURL, token, owner, repo string) (giteaRepo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s", serverURL, url.PathEscape(owner), url.PathEscape(repo))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return giteaRepo{}, err
 	}
-	return fmt.Errorf("create repo неуспешан (status %d): %s", resp.StatusCode, trimmed)
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", appName)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return giteaRepo{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return giteaRepo{}, fmt.Errorf("repo %s/%s не постоји или нема приступа", owner, repo)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return giteaRepo{}, fmt.Errorf("status %d: %s", resp.StatusCode, giteaErrorMessage(resp.Body))
+	}
+
+	var r giteaRepo
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return giteaRepo{}, err
+	}
+	return r, nil
+}
+
+// writeDefaultAppConfig writes the config.toml template with owner-only
+// permissions, since the token is stored there.
+func writeDefaultAppConfig(configPath string) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		return fmt.Errorf("креирање config директоријума: %w", err)
+	}
+	if err := os.WriteFile(configPath, []byte(defaultAppConfigContent()), 0o600); err != nil {
+		return fmt.Errorf("упис %s: %w", configPath, err)
+	}
+	return nil
+}
+
+func defaultAppConfigContent() string {
+	return strings.Join([]string{
+		"# gitcrn config",
+		fmt.Sprintf("server_url = %q", defaultServerURL),
+		"token = \"\"",
+		"",
+		fmt.Sprintf("ssh_alias = %q", defaultHostAlias),
+		fmt.Sprintf("ssh_host = %q", defaultHostName),
+		fmt.Sprintf("ssh_port = %d", defaultHostPort),
+		fmt.Sprintf("ssh_user = %q", defaultHostUser),
+		"# Tailscale име сервера; gitcrn init --tailscale из њега пише ssh_host и server_url",
+		"tailscale_node = \"\"",
+		"",
+		"# ssh или https (за clone/add)",
+		fmt.Sprintf("protocol = %q", defaultProtocol),
+		"",
+		"# колико често се проверава нова верзија",
+		fmt.Sprintf("update_check_interval = %q", defaultUpdateCheckInterval.String()),
+		"# stable или prerelease",
+		fmt.Sprintf("update_channel = %q", updateChannelStable),
+		"# github или gitea (gitea користи server_url, корисно без интернета)",
+		fmt.Sprintf("update_provider = %q", updateProviderGitHub),
+		fmt.Sprintf("update_owner = %q", defaultUpdateOwner),
+		fmt.Sprintf("update_repo = %q", defaultUpdateRepo),
+		"",
+		"# true: пре clone/push/pull пређи на доступан [[endpoint]] (gitcrn endpoint probe)",
+		"auto_endpoint = false",
+		"",
+		"# Друге адресе истог сервера, редом по предности (табеле иду на крај фајла):",
+		"# [[endpoint]]",
+		"# name = \"tailnet\"",
+		fmt.Sprintf("# ssh_host = %q", defaultHostName),
+		fmt.Sprintf("# ssh_port = %d", defaultHostPort),
+		fmt.Sprintf("# server_url = %q", defaultServerURL),
+		"#",
+		"# [[endpoint]]",
+		"# name = \"lan\"",
+		"# ssh_host = \"192.168.1.20\"",
+		"# ssh_port = 222",
+		"# server_url = \"http://192.168.1.20:5000\"",
+		"",
+	}, "\n")
 }
 
 func appConfigPath() (string, error) {
@@ -668,6 +1049,9 @@ func loadAppConfig() (appConfig, error) {
 		SSHHost:   defaultHostName,
 		SSHPort:   defaultHostPort,
 		SSHUser:   defaultHostUser,
+		Protocol:  defaultProtocol,
+
+		UpdateChannel: updateChannelStable,
 	}
 
 	path, err := appConfigPath()
@@ -683,6 +1067,10 @@ func loadAppConfig() (appConfig, error) {
 	}
 
 	lines := strings.Split(normalizeNewlines(string(data)), "\n")
+	var (
+		section  string
+		endpoint *endpointConfig
+	)
 	for _, raw := range lines {
 		line := strings.TrimSpace(raw)
 		if line == "" || strings.HasPrefix(line, "#") {
@@ -695,6 +1083,16 @@ func loadAppConfig() (appConfig, error) {
 			continue
 		}
 
+		if strings.HasPrefix(line, "[") {
+			section = strings.ReplaceAll(line, " ", "")
+			endpoint = nil
+			if section == "[[endpoint]]" {
+				cfg.Endpoints = append(cfg.Endpoints, endpointConfig{})
+				endpoint = &cfg.Endpoints[len(cfg.Endpoints)-1]
+			}
+			continue
+		}
+
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) != 2 {
 			continue
@@ -703,6 +1101,13 @@ func loadAppConfig() (appConfig, error) {
 		val := strings.TrimSpace(parts[1])
 		val = strings.Trim(strings.TrimSpace(val), "\"")
 
+		if section != "" {
+			if endpoint != nil {
+				endpoint.set(key, val)
+			}
+			continue
+		}
+
 		switch key {
 		case "server_url":
 			if val != "" {
@@ -726,8 +1131,37 @@ func loadAppConfig() (appConfig, error) {
 			if n, err := strconv.Atoi(val); err == nil && n > 0 && n <= 65535 {
 				cfg.SSHPort = n
 			}
+		case "protocol":
+			if p := strings.ToLower(val); p == "ssh" || p == "https" {
+				cfg.Protocol = p
+			}
+		case "tailscale_node":
+			cfg.TailscaleNode = val
+		case "endpoint":
+			cfg.Endpoint = val
+		case "auto_endpoint":
+			cfg.AutoEndpoint = strings.EqualFold(val, "true")
+		case "update_base_url":
+			cfg.UpdateBaseURL = val
+		case "update_provider":
+			if p := strings.ToLower(val); p == updateProviderGitHub || p == updateProviderGitea {
+				cfg.UpdateProvider = p
+			}
+		case "update_owner":
+			cfg.UpdateOwner = val
+		case "update_repo":
+			cfg.UpdateRepo = val
+		case "update_channel":
+			if c := strings.ToLower(val); c == updateChannelStable || c == updateChannelPrerelease {
+				cfg.UpdateChannel = c
+			}
+		case "update_check_interval":
+			if d, err := time.ParseDuration(val); err == nil && d > 0 {
+				cfg.UpdateCheckInterval = d
+			}
 		}
 	}
+	cfg.applyEndpoints()
 	return cfg, nil
 }
 
@@ -739,12 +1173,17 @@ func runMake(args []string, overwrite bool) error {
 		return runCreateRepo(args[1:])
 	}
 
-	fs := flag.NewFlagSet("make", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
+	fs := newFlagSet("make")
 
-	makePush := fs.Bool("push", false, "Направи push скрипту")
-	makePull := fs.Bool("pull", false, "Направи pull скрипту")
+	makePush := fs.Bool("push", false, "Подеси push")
+	makePull := fs.Bool("pull", false, "Подеси pull")
+	script := fs.Bool("script", false, "Извези push.sh/pull.sh (.ps1 на Windows-у) уместо .gitcrn.toml")
 	makeBoth := fs.Bool("pp", false, "Краћи облик за --push --pull")
+	branchFlag := fs.String("branch", "", "Грана (без питања)")
+	messageFlag := fs.String("message", "", "Порука за commit (без питања)")
+	pushRemotesFlag := fs.String("push-remotes", "", "Remote-и за push, зарез или размак (без питања)")
+	pullRemotesFlag := fs.String("pull-remotes", "", "Remote-и за pull, зарез или размак (без питања)")
+	yes := fs.Bool("yes", false, "За све што није задато флагом узми подразумевано")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -768,6 +1207,15 @@ func runMake(args []string, overwrite bool) error {
 		printMakeUsage(os.Stderr)
 		return errors.New("изабери бар једно: --push, --pull или -pp")
 	}
+	if *messageFlag != "" && !*makePush {
+		return errors.New("--message иде уз --push")
+	}
+	if *pushRemotesFlag != "" && !*makePush {
+		return errors.New("--push-remotes иде уз --push")
+	}
+	if *pullRemotesFlag != "" && !*makePull {
+		return errors.New("--pull-remotes иде уз --pull")
+	}
 
 	if strings.TrimSpace(commandOutput("git", "rev-parse", "--is-inside-work-tree")) != "true" {
 		return errors.New("ова команда мора да се покрене унутар git репозиторијума")
@@ -781,50 +1229,111 @@ func runMake(args []string, overwrite bool) error {
 		fmt.Println(remoteOutput)
 	}
 
+	// A value given as a flag is never asked for; --yes takes the default
+	// for the rest. Without a terminal the answers have to be piped in, and
+	// running out of them is an error rather than a silent default.
+	ask := func(flagValue, label, def, what string) (string, error) {
+		if v := strings.TrimSpace(flagValue); v != "" {
+			return v, nil
+		}
+		if *yes {
+			return def, nil
+		}
+		v, err := promptInput(os.Stdout, stdin, label, def)
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("читање %s: stdin је завршен пре одговора; без терминала наведи --branch, --message, --push-remotes, --pull-remotes или --yes", what)
+		}
+		if err != nil {
+			return "", fmt.Errorf("читање %s: %w", what, err)
+		}
+		return v, nil
+	}
+
 	fetchRemotes, pushRemotes := parseRemoteNames(remoteOutput)
+	defPushRemotes := preferNonEmpty(pushRemotes, fetchRemotes)
+	defPullRemotes := preferNonEmpty(fetchRemotes, pushRemotes)
 	defaultBranch := strings.TrimSpace(commandOutput("git", "branch", "--show-current"))
-	branch, err := promptInput(os.Stdout, os.Stdin, "Грана", defaultBranch)
+	defaultMessage := defaultCommitMsg
+
+	// An existing .gitcrn.toml supplies the defaults, so remake only asks
+	// to confirm what is already there.
+	root, err := gitRepoRoot()
+	if err != nil {
+		return err
+	}
+	existing, found, err := loadRepoConfig(root)
 	if err != nil {
-		return fmt.Errorf("читање гране: %w", err)
+		return err
+	}
+	if found {
+		defaultBranch = fallback(existing.Branch, defaultBranch)
+		defaultMessage = fallback(existing.Message, defaultMessage)
+		defPushRemotes = preferNonEmpty(existing.PushRemotes, defPushRemotes)
+		defPullRemotes = preferNonEmpty(existing.PullRemotes, defPullRemotes)
+		if !*script && !overwrite && (*makePush && len(existing.PushRemotes) > 0 || *makePull && len(existing.PullRemotes) > 0) {
+			return fmt.Errorf("%s већ постоји. Користи: %s remake", repoConfigName, appName)
+		}
 	}
 
-	created := make([]string, 0, 2)
+	branch, err := ask(*branchFlag, "Грана", defaultBranch, "гране")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(branch) == "" {
+		return errors.New("грана није позната (detached HEAD?); наведи --branch")
+	}
 
+	var commitMsg string
+	var pushList, pullList []string
 	if *makePush {
-		commitMsg, err := promptInput(os.Stdout, os.Stdin, "Порука за commit", defaultCommitMsg)
-		if err != nil {
-			return fmt.Errorf("читање commit поруке: %w", err)
+		if commitMsg, err = ask(*messageFlag, "Порука за commit", defaultMessage, "commit поруке"); err != nil {
+			return err
 		}
-
-		defPushRemotes := strings.Join(preferNonEmpty(pushRemotes, fetchRemotes), ",")
-		remotesText, err := promptInput(os.Stdout, os.Stdin, "Remote-и за push (зарез или размак)", defPushRemotes)
+		remotesText, err := ask(*pushRemotesFlag, "Remote-и за push (зарез или размак)", strings.Join(defPushRemotes, ","), "push remote-а")
 		if err != nil {
-			return fmt.Errorf("читање push remote-а: %w", err)
+			return err
 		}
-		remotes := parseRemoteList(remotesText)
-		if len(remotes) == 0 {
+		if pushList = parseRemoteList(remotesText); len(pushList) == 0 {
 			return errors.New("push захтева бар један remote")
 		}
+	}
+	if *makePull {
+		remotesText, err := ask(*pullRemotesFlag, "Remote-и за pull (зарез или размак)", strings.Join(defPullRemotes, ","), "pull remote-а")
+		if err != nil {
+			return err
+		}
+		if pullList = parseRemoteList(remotesText); len(pullList) == 0 {
+			return errors.New("pull захтева бар један remote")
+		}
+	}
 
-		path, err := writePushScript(commitMsg, branch, remotes, overwrite)
+	created := make([]string, 0, 2)
+	if !*script {
+		values := []configEntry{{"branch", branch}}
+		if *makePush {
+			values = append(values, configEntry{"message", commitMsg}, configEntry{"push_remotes", pushList})
+		}
+		if *makePull {
+			values = append(values, configEntry{"pull_remotes", pullList})
+		}
+		path, err := saveRepoConfig(root, values)
 		if err != nil {
 			return err
 		}
 		created = append(created, path)
 	}
 
-	if *makePull {
-		defPullRemotes := strings.Join(preferNonEmpty(fetchRemotes, pushRemotes), ",")
-		remotesText, err := promptInput(os.Stdout, os.Stdin, "Remote-и за pull (зарез или размак)", defPullRemotes)
+	// --script exports the same settings as push.sh/pull.sh (.ps1 on
+	// Windows) for machines without gitcrn.
+	if *script && *makePush {
+		path, err := writePushScript(commitMsg, branch, pushList, overwrite)
 		if err != nil {
-			return fmt.Errorf("читање pull remote-а: %w", err)
-		}
-		remotes := parseRemoteList(remotesText)
-		if len(remotes) == 0 {
-			return errors.New("pull захтева бар један remote")
+			return err
 		}
-
-		path, err := writePullScript(branch, remotes, overwrite)
+		created = append(created, path)
+	}
+	if *script && *makePull {
+		path, err := writePullScript(branch, pullList, overwrite)
 		if err != nil {
 			return err
 		}
@@ -837,15 +1346,25 @@ func runMake(args []string, overwrite bool) error {
 	return nil
 }
 
-func promptInput(w io.Writer, r io.Reader, label, defaultValue string) (string, error) {
+// stdin is the only reader over os.Stdin. A bufio.Reader reads ahead, so a
+// new one per prompt would swallow the answers piped for the next prompts.
+var stdin = bufio.NewReader(os.Stdin)
+
+// promptInput returns defaultValue for an empty answer. When input ends
+// before any answer it returns io.EOF instead, so a closed or exhausted stdin
+// is not mistaken for the user accepting the default.
+func promptInput(w io.Writer, r *bufio.Reader, label, defaultValue string) (string, error) {
 	if strings.TrimSpace(defaultValue) != "" {
 		fmt.Fprintf(w, "%s [%s]: ", label, defaultValue)
 	} else {
 		fmt.Fprintf(w, "%s: ", label)
 	}
 
-	reader := bufio.NewReader(r)
-	line, err := reader.ReadString('\n')
+	line, err := r.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		fmt.Fprintln(w)
+		return "", io.EOF
+	}
 	if err != nil && !errors.Is(err, io.EOF) {
 		return "", err
 	}
@@ -1072,38 +1591,89 @@ func psSingleQuote(s string) string {
 }
 
 func runClone(args []string) error {
-	if len(args) < 1 {
+	fs := newFlagSet("clone")
+	useHTTPS := fs.Bool("https", false, "Клонирај преко HTTPS уместо SSH")
+
+	// Flags before owner/repo are gitcrn's own. After it, --https and the
+	// output flags still work and everything else goes to git clone as at
+	// baseline: `gitcrn clone owner/repo dir --depth 1`.
+	err := fs.Parse(args)
+	var gitExtra []string
+	if err == nil && fs.NArg() > 0 {
+		gitExtra, err = splitKnownFlags(fs, fs.Args()[1:])
+	}
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printCloneUsage(os.Stdout)
+			return nil
+		}
+		printCloneUsage(os.Stderr)
+		return err
+	}
+	if fs.NArg() < 1 {
 		printCloneUsage(os.Stderr)
 		return errors.New("clone тражи owner/repo")
 	}
 
-	repoURL, err := buildRepoURL(args[0])
+	cfg, err := loadAppConfig()
 	if err != nil {
 		return err
 	}
-
-	gitArgs := []string{"clone", repoURL}
-	if len(args) > 1 {
-		gitArgs = append(gitArgs, args[1:]...)
+	// The endpoint is picked first so an HTTPS clone uses its server_url.
+	cfg = autoSelectEndpoint(cfg)
+	repoURL, err := resolveRepoURL(cfg, fs.Arg(0), *useHTTPS)
+	if err != nil {
+		return err
+	}
+	if err := ensureTailnetUp(repoHost(cfg, *useHTTPS)); err != nil {
+		return err
 	}
 
-	return runGit(gitArgs...)
+	return runGit(append([]string{"clone", repoURL}, gitExtra...)...)
 }
 
 func runPush(args []string) error {
-	if len(args) != 0 {
+	fs := newFlagSet("push")
+	script := fs.Bool("script", false, "Покрени push.sh/push.ps1 и кад постоји .gitcrn.toml")
+	timeout := fs.Duration("timeout", 0, "Најдуже трајање push-а по remote-у")
+	required := fs.String("required", "", "Remote-и чији пад обара push (зарезом одвојени)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printPushUsage(os.Stdout)
+			return nil
+		}
+		printPushUsage(os.Stderr)
+		return err
+	}
+	if fs.NArg() != 0 {
 		printPushUsage(os.Stderr)
 		return errors.New("push не прима додатне аргументе")
 	}
-	return runGeneratedScript("push")
+	if *timeout < 0 {
+		printPushUsage(os.Stderr)
+		return errors.New("--timeout мора бити позитиван")
+	}
+	return runSync("push", syncOptions{Script: *script, Timeout: *timeout, Required: parseRemoteList(*required)})
 }
 
 func runPull(args []string) error {
-	if len(args) != 0 {
+	fs := newFlagSet("pull")
+	script := fs.Bool("script", false, "Покрени pull.sh/pull.ps1 и кад постоји .gitcrn.toml")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printPullUsage(os.Stdout)
+			return nil
+		}
+		printPullUsage(os.Stderr)
+		return err
+	}
+	if fs.NArg() != 0 {
 		printPullUsage(os.Stderr)
 		return errors.New("pull не прима додатне аргументе")
 	}
-	return runGeneratedScript("pull")
+	return runSync("pull", syncOptions{Script: *script})
 }
 
 func runCompletion(args []string) error {
@@ -1132,13 +1702,22 @@ _%s() {
     'generate:Генериши подешавања'
     'create:Креирај ресурсе'
     'repo:Repo namespace команде'
+    'browse:Отвори repo у browser-у'
+    'api:Директан Gitea API позив'
+    'issue:Рад са issue-има'
+    'pr:Рад са pull request-овима'
+    'release:Рад са release-овима'
+    'config:Прикажи подешавања'
+    'endpoint:Адресе сервера (list, probe, use)'
+    'self-update:Ажурирај gitcrn'
     'doctor:Провера окружења'
-    'make:Генериши push/pull скрипте или make repo'
-    'remake:Препиши push/pull скрипте'
+    'make:Подеси push/pull (.gitcrn.toml) или make repo'
+    'remake:Препиши push/pull подешавања'
     'init:Подеси SSH alias %s'
+    'uninit:Уклони оно што је init подесио'
     'clone:Клонирај owner/repo преко SSH'
-    'push:Покрени push.sh/push.ps1'
-    'pull:Покрени pull.sh/pull.ps1'
+    'push:Commit и push на remote-е из .gitcrn.toml'
+    'pull:Pull са remote-а из .gitcrn.toml'
     'add:Додај remote %s'
     'completion:Генериши shell completion'
     '-gc:Краћи облик за generate config'
@@ -1152,6 +1731,8 @@ _%s() {
   root_flags=(
     '-h[Помоћ]'
     '--help[Помоћ]'
+    '--json[JSON излаз]'
+    '--format[Формат излаза]:format:(json table plain)'
   )
 
   local curcontext="$curcontext" state line
@@ -1167,7 +1748,10 @@ _%s() {
     args)
       case "$line[1]" in
         init)
-          _arguments '--default[Подразумевана SSH подешавања]' '--custom[Прилагођена SSH подешавања]' '--host[SSH HostName]:host:' '--port[SSH порт]:port:' '--user[SSH корисник]:user:'
+          _arguments '--default[Подразумевана SSH подешавања]' '--custom[Прилагођена SSH подешавања]' '--host[SSH HostName]:host:' '--port[SSH порт]:port:' '--user[SSH корисник]:user:' '--tailscale[Tailscale машина]:машина:' '--magicdns[MagicDNS име уместо IP-а]' '--dry-run[Само прикажи diff]'
+          ;;
+        uninit)
+          _arguments '--config[Обриши config.toml]' '--known-hosts[Уклони сервер из known_hosts]' '--revoke-token[Опозови token]' '--all[Све наведено]' '--yes[Без потврде]' '--dry-run[Само прикажи план]'
           ;;
         completion)
           _values 'shell' zsh bash fish
@@ -1190,24 +1774,108 @@ _%s() {
             create)
               _arguments '--private[Креирај private репозиторијум]' '--public[Креирај public репозиторијум]' '--desc[Опис]:опис:' '--default-branch[Грана]:грана:' '--clone[Одмах клонирај]'
               ;;
+            list|ls)
+              _arguments '--limit[Лимит]:n:' '--json[JSON излаз]' '--format[Формат излаза]:format:(json table plain)'
+              ;;
             *)
-              _values 'подкоманда' create
+              _values 'подкоманда' create view list
               ;;
           esac
           ;;
+        config)
+          _values 'подкоманда' list path
+          ;;
+        endpoint)
+          _arguments '1:подкоманда:(list probe use)' '--timeout[Timeout]:трајање:' '--no-switch[Само измери]'
+          ;;
+        doctor)
+          _arguments '--strict[Упозорења су грешке]' '--fix[Понуди поправке]' '--yes[Без питања]' '--only[Групе]:групе:(tailscale git ssh config security net)' '--timeout[Timeout]:трајање:' '--json[JSON излаз]' '--format[Формат излаза]:format:(json table plain)'
+          ;;
         make)
           _arguments '1:подкоманда/опција:(repo --push --pull -pp)' '*::аргумент:->makeargs'
           case "$line[2]" in
             repo)
               _arguments '--private[Креирај private репозиторијум]' '--public[Креирај public репозиторијум]' '--desc[Опис]:опис:' '--default-branch[Грана]:грана:' '--clone[Одмах клонирај]'
               ;;
+            *)
+              _arguments '--push[Подеси push]' '--pull[Подеси pull]' '-pp[И push и pull]' '--branch[Грана]:грана:' '--message[Commit порука]:порука:' '--push-remotes[Remote-и за push]:remote-и:' '--pull-remotes[Remote-и за pull]:remote-и:' '--yes[Подразумевано без питања]' '--script[Извези скрипте]'
+              ;;
           esac
           ;;
         remake)
-          _arguments '--push[Генериши push скрипту]' '--pull[Генериши pull скрипту]' '-pp[И push и pull]'
+          _arguments '--push[Подеси push]' '--pull[Подеси pull]' '-pp[И push и pull]' '--branch[Грана]:грана:' '--message[Commit порука]:порука:' '--push-remotes[Remote-и за push]:remote-и:' '--pull-remotes[Remote-и за pull]:remote-и:' '--yes[Подразумевано без питања]' '--script[Извези скрипте]'
+          ;;
+        push)
+          _arguments '--script[Покрени генерисану скрипту]' '--timeout[Timeout по remote-у]:трајање:' '--required[Обавезни remote-и]:remote-и:'
+          ;;
+        pull)
+          _arguments '--script[Покрени генерисану скрипту]'
+          ;;
+        issue)
+          case "$line[2]" in
+            list|ls)
+              _arguments '--repo[owner/repo]:repo:' '--state[Стање]:state:(open closed all)' '--label[Лабеле]:label:' '--milestone[Milestone]:milestone:' '--assignee[Додељено]:user:' '--author[Аутор]:user:' '--search[Претрага]:q:' '--limit[Лимит]:n:' '--json[JSON излаз]'
+              ;;
+            create|new)
+              _arguments '--repo[owner/repo]:repo:' '--title[Наслов]:title:' '--body[Опис]:body:' '--body-file[Опис из фајла]:file:_files' '--label[Лабеле]:label:' '--assignee[Додељено]:user:' '--milestone[Milestone]:milestone:'
+              ;;
+            view|comment|close|reopen|edit)
+              _arguments '--repo[owner/repo]:repo:' '--comments[Коментари]' '--json[JSON излаз]' '--body[Текст]:body:' '--body-file[Фајл]:file:_files' '--comment[Коментар]:comment:' '--title[Наслов]:title:' '--add-label[Додај лабелу]:label:' '--remove-label[Уклони лабелу]:label:' '--assignee[Додељено]:user:' '--milestone[Milestone]:milestone:' '--clear-milestone[Уклони milestone]'
+              ;;
+            *)
+              _values 'подкоманда' list view create comment close reopen edit
+              ;;
+          esac
+          ;;
+        pr)
+          case "$line[2]" in
+            create|new)
+              _arguments '--repo[owner/repo]:repo:' '--head[Head грана]:branch:' '--base[Base грана]:branch:' '--title[Наслов]:title:' '--body[Опис]:body:' '--body-file[Опис из фајла]:file:_files' '--label[Лабеле]:label:' '--assignee[Додељено]:user:' '--fill[Из commit-а]'
+              ;;
+            list|ls)
+              _arguments '--repo[owner/repo]:repo:' '--state[Стање]:state:(open closed all)' '--label[Лабеле]:label:' '--limit[Лимит]:n:' '--json[JSON излаз]'
+              ;;
+            merge)
+              _arguments '--repo[owner/repo]:repo:' '--style[Стил]:style:(merge rebase rebase-merge squash)' '--title[Наслов]:title:' '--message[Порука]:message:' '--delete-branch[Обриши грану]'
+              ;;
+            view|checkout|co|diff)
+              _arguments '--repo[owner/repo]:repo:' '--json[JSON излаз]' '--branch[Локална грана]:branch:' '--remote[Remote]:remote:' '--patch[Patch формат]'
+              ;;
+            *)
+              _values 'подкоманда' create list view checkout merge diff
+              ;;
+          esac
+          ;;
+        release)
+          case "$line[2]" in
+            create|new)
+              _arguments '--repo[owner/repo]:repo:' '--title[Наслов]:title:' '--notes[Белешке]:notes:' '--notes-file[Белешке из фајла]:file:_files' '--target[Грана]:branch:' '--draft[Draft]' '--prerelease[Prerelease]' '*:фајл:_files'
+              ;;
+            list|ls)
+              _arguments '--repo[owner/repo]:repo:' '--limit[Лимит]:n:' '--json[JSON излаз]'
+              ;;
+            download)
+              _arguments '--repo[owner/repo]:repo:' '--pattern[Glob]:pattern:' '--dir[Директоријум]:dir:_files -/' '--skip-verify[Без SHA-256 провере]'
+              ;;
+            delete|rm)
+              _arguments '--repo[owner/repo]:repo:' '--yes[Без потврде]' '--cleanup-tag[Обриши и tag]'
+              ;;
+            *)
+              _values 'подкоманда' create list download delete
+              ;;
+          esac
+          ;;
+        self-update)
+          _arguments '--version[Верзија]:version:' '--rollback[Врати претходну верзију]' '--force[Инсталирај поново]' '--channel[Канал]:channel:(stable prerelease)'
+          ;;
+        api)
+          _arguments '-f[Поље key=value]:поље:' '-F[Типизирано поље key=value]:поље:' '-H[HTTP header]:header:' '--input[JSON тело]:фајл:_files' '--paginate[Прати странице]' '-q[Филтер]:филтер:' '--silent[Без излаза]' '1:method:(GET POST PUT PATCH DELETE)'
+          ;;
+        browse)
+          _arguments '--issues[Issues]' '--pulls[Pull request-ови]' '--releases[Releases]' '--settings[Подешавања]' '--commit[Commit]:sha:' '--branch[Грана]:грана:' '--print[Само испиши URL]' '*:path:_files'
           ;;
         clone|add)
-          _message 'owner/repo'
+          _arguments '--https[Користи HTTPS уместо SSH]' '1:owner/repo:'
           ;;
       esac
       ;;
@@ -1227,8 +1895,13 @@ _%s "$@"
   words=("${COMP_WORDS[@]}")
   cword=$COMP_CWORD
 
-  local root_cmds="generate create repo doctor make remake init clone push pull add completion -gc -pp -v --version help"
-  local opts="-h --help"
+  local root_cmds="generate create repo config endpoint browse api issue pr release self-update doctor make remake init uninit clone push pull add completion -gc -pp -v --version help"
+  local opts="-h --help --json --format"
+
+  if [[ "$prev" == "--format" ]]; then
+    COMPREPLY=( $(compgen -W "json table plain" -- "$cur") )
+    return
+  fi
 
   if [[ $cword -eq 1 ]]; then
     COMPREPLY=( $(compgen -W "$root_cmds $opts" -- "$cur") )
@@ -1237,7 +1910,10 @@ _%s "$@"
 
   case "${words[1]}" in
     init)
-      COMPREPLY=( $(compgen -W "--default --custom --host --port --user -h --help" -- "$cur") )
+      COMPREPLY=( $(compgen -W "--default --custom --tailscale --magicdns --dry-run --host --port --user -h --help" -- "$cur") )
+      ;;
+    uninit)
+      COMPREPLY=( $(compgen -W "--config --known-hosts --revoke-token --all --yes --dry-run -h --help" -- "$cur") )
       ;;
     completion)
       COMPREPLY=( $(compgen -W "zsh bash fish" -- "$cur") )
@@ -1254,25 +1930,78 @@ _%s "$@"
       ;;
     repo)
       if [[ $cword -eq 2 ]]; then
-        COMPREPLY=( $(compgen -W "create -h --help" -- "$cur") )
+        COMPREPLY=( $(compgen -W "create view list -h --help" -- "$cur") )
+      elif [[ "${words[2]}" == "list" || "${words[2]}" == "ls" ]]; then
+        COMPREPLY=( $(compgen -W "--limit --json --format -h --help" -- "$cur") )
       else
         COMPREPLY=( $(compgen -W "--private --public --desc --default-branch --clone -h --help" -- "$cur") )
       fi
       ;;
+    config)
+      COMPREPLY=( $(compgen -W "list path --json --format -h --help" -- "$cur") )
+      ;;
+    endpoint)
+      COMPREPLY=( $(compgen -W "list probe use --timeout --no-switch --json --format -h --help" -- "$cur") )
+      ;;
+    doctor)
+      if [[ "$prev" == "--only" ]]; then
+        COMPREPLY=( $(compgen -W "tailscale git ssh config security net" -- "$cur") )
+      else
+        COMPREPLY=( $(compgen -W "--strict --only --timeout --fix --yes --json --format -h --help" -- "$cur") )
+      fi
+      ;;
     make)
       if [[ $cword -eq 2 ]]; then
         COMPREPLY=( $(compgen -W "repo --push --pull -pp -h --help" -- "$cur") )
       elif [[ "${words[2]}" == "repo" ]]; then
         COMPREPLY=( $(compgen -W "--private --public --desc --default-branch --clone -h --help" -- "$cur") )
       else
-        COMPREPLY=( $(compgen -W "--push --pull -pp -h --help" -- "$cur") )
+        COMPREPLY=( $(compgen -W "--push --pull -pp --branch --message --push-remotes --pull-remotes --yes --script -h --help" -- "$cur") )
       fi
       ;;
     remake)
-      COMPREPLY=( $(compgen -W "--push --pull -pp -h --help" -- "$cur") )
+      COMPREPLY=( $(compgen -W "--push --pull -pp --branch --message --push-remotes --pull-remotes --yes --script -h --help" -- "$cur") )
+      ;;
+    push)
+      COMPREPLY=( $(compgen -W "--script --timeout --required -h --help" -- "$cur") )
+      ;;
+    pull)
+      COMPREPLY=( $(compgen -W "--script -h --help" -- "$cur") )
+      ;;
+    issue)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "list view create comment close reopen edit -h --help" -- "$cur") )
+      else
+        COMPREPLY=( $(compgen -W "--repo --state --label --milestone --assignee --author --search --limit --json --comments --title --body --body-file --comment --add-label --remove-label --clear-milestone -h --help" -- "$cur") )
+      fi
+      ;;
+    pr)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "create list view checkout merge diff -h --help" -- "$cur") )
+      elif [[ "$prev" == "--style" ]]; then
+        COMPREPLY=( $(compgen -W "merge rebase rebase-merge squash" -- "$cur") )
+      else
+        COMPREPLY=( $(compgen -W "--repo --head --base --title --body --body-file --label --assignee --fill --state --limit --json --branch --remote --style --message --delete-branch --patch -h --help" -- "$cur") )
+      fi
+      ;;
+    release)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "create list download delete -h --help" -- "$cur") )
+      else
+        COMPREPLY=( $(compgen -W "--repo --title --notes --notes-file --target --draft --prerelease --limit --json --pattern --dir --skip-verify --yes --cleanup-tag -h --help" -- "$cur") $(compgen -f -- "$cur") )
+      fi
+      ;;
+    self-update)
+      COMPREPLY=( $(compgen -W "--version --rollback --force --channel -h --help" -- "$cur") )
+      ;;
+    api)
+      COMPREPLY=( $(compgen -W "GET POST PUT PATCH DELETE -f -F -H --input --paginate -q --silent -h --help" -- "$cur") )
+      ;;
+    browse)
+      COMPREPLY=( $(compgen -W "--issues --pulls --releases --settings --commit --branch --print -h --help" -- "$cur") $(compgen -f -- "$cur") )
       ;;
     clone|add)
-      COMPREPLY=()
+      COMPREPLY=( $(compgen -W "--https -h --help" -- "$cur") )
       ;;
   esac
 }
@@ -1281,11 +2010,23 @@ complete -F _%s_complete %s
 `, appName, appName, appName), nil
 	case "fish":
 		return fmt.Sprintf(`complete -c %s -f
-complete -c %s -n "__fish_use_subcommand" -a "generate create repo doctor make remake init clone push pull add completion -gc -pp -v --version help"
+complete -c %s -n "__fish_use_subcommand" -a "generate create repo config endpoint browse api issue pr release self-update doctor make remake init uninit clone push pull add completion -gc -pp -v --version help"
 complete -c %s -n "__fish_seen_subcommand_from completion" -a "zsh bash fish"
 complete -c %s -n "__fish_seen_subcommand_from generate" -a "config"
 complete -c %s -n "__fish_seen_subcommand_from create" -a "repo"
-complete -c %s -n "__fish_seen_subcommand_from repo" -a "create"
+complete -c %s -n "__fish_seen_subcommand_from repo" -a "create view list"
+complete -c %s -n "__fish_seen_subcommand_from repo; and __fish_seen_subcommand_from list" -l limit -r
+complete -c %s -n "__fish_seen_subcommand_from config" -a "list path"
+complete -c %s -n "__fish_seen_subcommand_from endpoint" -a "list probe use"
+complete -c %s -n "__fish_seen_subcommand_from endpoint" -l timeout -r
+complete -c %s -n "__fish_seen_subcommand_from endpoint" -l no-switch
+complete -c %s -n "__fish_seen_subcommand_from doctor" -l strict
+complete -c %s -n "__fish_seen_subcommand_from doctor" -l only -r -a "tailscale git ssh config security net"
+complete -c %s -n "__fish_seen_subcommand_from doctor" -l fix
+complete -c %s -n "__fish_seen_subcommand_from doctor" -l yes
+complete -c %s -n "__fish_seen_subcommand_from doctor" -l timeout -r
+complete -c %s -l json -d "JSON излаз"
+complete -c %s -l format -r -a "json table plain" -d "Формат излаза"
 complete -c %s -n "__fish_seen_subcommand_from make" -a "repo"
 complete -c %s -n "__fish_seen_subcommand_from create; and __fish_seen_subcommand_from repo" -l private
 complete -c %s -n "__fish_seen_subcommand_from create; and __fish_seen_subcommand_from repo" -l public
@@ -1305,36 +2046,101 @@ complete -c %s -n "__fish_seen_subcommand_from make; and __fish_seen_subcommand_
 complete -c %s -n "__fish_seen_subcommand_from make remake" -l push
 complete -c %s -n "__fish_seen_subcommand_from make remake" -l pull
 complete -c %s -n "__fish_seen_subcommand_from make remake" -o pp
+complete -c %s -n "__fish_seen_subcommand_from make remake" -l branch -r
+complete -c %s -n "__fish_seen_subcommand_from make remake" -l message -r
+complete -c %s -n "__fish_seen_subcommand_from make remake" -l push-remotes -r
+complete -c %s -n "__fish_seen_subcommand_from make remake" -l pull-remotes -r
+complete -c %s -n "__fish_seen_subcommand_from make remake" -l yes
+complete -c %s -n "__fish_seen_subcommand_from make remake" -l script
+complete -c %s -n "__fish_seen_subcommand_from push pull" -l script
+complete -c %s -n "__fish_seen_subcommand_from push" -l timeout -r
+complete -c %s -n "__fish_seen_subcommand_from push" -l required -r
 complete -c %s -n "__fish_seen_subcommand_from init" -l default
 complete -c %s -n "__fish_seen_subcommand_from init" -l custom
 complete -c %s -n "__fish_seen_subcommand_from init" -l host -r
 complete -c %s -n "__fish_seen_subcommand_from init" -l port -r
 complete -c %s -n "__fish_seen_subcommand_from init" -l user -r
-`, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName), nil
+complete -c %s -n "__fish_seen_subcommand_from init" -l tailscale -r
+complete -c %s -n "__fish_seen_subcommand_from init" -l magicdns
+complete -c %s -n "__fish_seen_subcommand_from init" -l dry-run
+complete -c %s -n "__fish_seen_subcommand_from uninit" -l config
+complete -c %s -n "__fish_seen_subcommand_from uninit" -l known-hosts
+complete -c %s -n "__fish_seen_subcommand_from uninit" -l revoke-token
+complete -c %s -n "__fish_seen_subcommand_from uninit" -l all
+complete -c %s -n "__fish_seen_subcommand_from uninit" -l yes
+complete -c %s -n "__fish_seen_subcommand_from uninit" -l dry-run
+complete -c %s -n "__fish_seen_subcommand_from clone add" -l https
+complete -c %s -n "__fish_seen_subcommand_from browse" -l issues
+complete -c %s -n "__fish_seen_subcommand_from browse" -l pulls
+complete -c %s -n "__fish_seen_subcommand_from browse" -l releases
+complete -c %s -n "__fish_seen_subcommand_from browse" -l settings
+complete -c %s -n "__fish_seen_subcommand_from browse" -l commit -r
+complete -c %s -n "__fish_seen_subcommand_from browse" -l branch -r
+complete -c %s -n "__fish_seen_subcommand_from browse" -l print
+complete -c %s -n "__fish_seen_subcommand_from api" -l paginate
+complete -c %s -n "__fish_seen_subcommand_from api" -l input -r
+complete -c %s -n "__fish_seen_subcommand_from api" -l silent
+complete -c %s -n "__fish_seen_subcommand_from issue" -a "list view create comment close reopen edit"
+complete -c %s -n "__fish_seen_subcommand_from issue" -l repo -r
+complete -c %s -n "__fish_seen_subcommand_from issue" -l state -r -a "open closed all"
+complete -c %s -n "__fish_seen_subcommand_from issue" -l label -r
+complete -c %s -n "__fish_seen_subcommand_from issue" -l assignee -r
+complete -c %s -n "__fish_seen_subcommand_from issue" -l milestone -r
+complete -c %s -n "__fish_seen_subcommand_from issue" -l title -r
+complete -c %s -n "__fish_seen_subcommand_from issue" -l body -r
+complete -c %s -n "__fish_seen_subcommand_from issue" -l body-file -r
+complete -c %s -n "__fish_seen_subcommand_from issue" -l json
+complete -c %s -n "__fish_seen_subcommand_from pr" -a "create list view checkout merge diff"
+complete -c %s -n "__fish_seen_subcommand_from pr" -l repo -r
+complete -c %s -n "__fish_seen_subcommand_from pr" -l head -r
+complete -c %s -n "__fish_seen_subcommand_from pr" -l base -r
+complete -c %s -n "__fish_seen_subcommand_from pr" -l title -r
+complete -c %s -n "__fish_seen_subcommand_from pr" -l fill
+complete -c %s -n "__fish_seen_subcommand_from pr" -l style -r -a "merge rebase rebase-merge squash"
+complete -c %s -n "__fish_seen_subcommand_from pr" -l delete-branch
+complete -c %s -n "__fish_seen_subcommand_from pr" -l json
+complete -c %s -n "__fish_seen_subcommand_from release" -a "create list download delete"
+complete -c %s -n "__fish_seen_subcommand_from release" -l repo -r
+complete -c %s -n "__fish_seen_subcommand_from release" -l title -r
+complete -c %s -n "__fish_seen_subcommand_from release" -l notes -r
+complete -c %s -n "__fish_seen_subcommand_from release" -l notes-file -r
+complete -c %s -n "__fish_seen_subcommand_from release" -l draft
+complete -c %s -n "__fish_seen_subcommand_from release" -l prerelease
+complete -c %s -n "__fish_seen_subcommand_from release" -l pattern -r
+complete -c %s -n "__fish_seen_subcommand_from release" -l dir -r
+complete -c %s -n "__fish_seen_subcommand_from release" -l skip-verify
+complete -c %s -n "__fish_seen_subcommand_from release" -l yes
+complete -c %s -n "__fish_seen_subcommand_from release" -l cleanup-tag
+complete -c %s -n "__fish_seen_subcommand_from self-update" -l version -r
+complete -c %s -n "__fish_seen_subcommand_from self-update" -l rollback
+complete -c %s -n "__fish_seen_subcommand_from self-update" -l force
+complete -c %s -n "__fish_seen_subcommand_from self-update" -l channel -r -a "stable prerelease"
+`, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName), nil
 	default:
 		return "", fmt.Errorf("неподржан shell: %s (подржано: zsh, bash, fish)", shell)
 	}
 }
 
-func runGeneratedScript(kind string) error {
-	var path string
-	var cmd *exec.Cmd
-
+// generatedScript returns the push/pull script written by make -pp and the
+// command that runs it.
+func generatedScript(kind string) (string, *exec.Cmd, error) {
 	switch runtime.GOOS {
 	case "windows":
-		path = kind + ".ps1"
+		path := kind + ".ps1"
 		if !fileExists(path) {
-			return fmt.Errorf("%s није пронађен. Покрени: gitcrn make -pp", path)
+			return "", nil, fmt.Errorf("%s није пронађен. Покрени: gitcrn make -pp", path)
 		}
-		cmd = exec.Command("powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", path)
+		return path, exec.Command("powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", path), nil
 	default:
-		path = kind + ".sh"
+		path := kind + ".sh"
 		if !fileExists(path) {
-			return fmt.Errorf("%s није пронађен. Покрени: gitcrn make -pp", path)
+			return "", nil, fmt.Errorf("%s није пронађен. Покрени: gitcrn make -pp", path)
 		}
-		cmd = exec.Command("bash", path)
+		return path, exec.Command("bash", path), nil
 	}
+}
 
+func runGeneratedScript(path string, cmd *exec.Cmd) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
@@ -1346,12 +2152,28 @@ func runGeneratedScript(kind string) error {
 }
 
 func runAdd(args []string) error {
-	if len(args) != 1 {
+	fs := newFlagSet("add")
+	useHTTPS := fs.Bool("https", false, "Додај remote преко HTTPS уместо SSH")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printAddUsage(os.Stdout)
+			return nil
+		}
+		printAddUsage(os.Stderr)
+		return err
+	}
+	if len(positional) != 1 {
 		printAddUsage(os.Stderr)
 		return errors.New("add тражи owner/repo")
 	}
 
-	repoURL, err := buildRepoURL(args[0])
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return err
+	}
+	repoURL, err := resolveRepoURL(cfg, positional[0], *useHTTPS)
 	if err != nil {
 		return err
 	}
@@ -1383,140 +2205,17 @@ func runRemote(args []string) error {
 }
 
 func shouldCheckUpdates(cmd string) bool {
-	if os.Getenv("GITCRN_NO_UPDATE_CHECK") != "" {
+	if os.Getenv("GITCRN_NO_UPDATE_CHECK") != "" || machineOutput() {
 		return false
 	}
 	switch cmd {
-	case "completion":
+	case "completion", "self-update", updateCheckCommand:
 		return false
 	default:
 		return true
 	}
 }
 
-func maybePrintUpdateNotice() {
-	latestTag, err := fetchLatestReleaseTag(1200 * time.Millisecond)
-	if err != nil {
-		return
-	}
-
-	cmp, ok := compareSemver(latestTag, version)
-	if !ok || cmp <= 0 {
-		return
-	}
-
-	fmt.Println(colorize(fmt.Sprintf("Доступна је нова верзија: %s (тренутна %s)", latestTag, version), ansiYellow, stdoutColor))
-	fmt.Println("Ажурирај овом командом:")
-	if runtime.GOOS == "windows" {
-		fmt.Printf("  %s\n", updateWinCmd)
-		return
-	}
-	fmt.Printf("  %s\n", updateLinuxCmd)
-}
-
-func fetchLatestReleaseTag(timeout time.Duration) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseAPI, nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("User-Agent", appName)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("status %d", resp.StatusCode)
-	}
-
-	var payload struct {
-		TagName string `json:"tag_name"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return "", err
-	}
-	if strings.TrimSpace(payload.TagName) == "" {
-		return "", errors.New("empty tag_name")
-	}
-	return strings.TrimSpace(payload.TagName), nil
-}
-
-func compareSemver(a, b string) (int, bool) {
-	av, okA := parseVersionParts(a)
-	bv, okB := parseVersionParts(b)
-	if !okA || !okB {
-		return 0, false
-	}
-
-	maxLen := len(av)
-	if len(bv) > maxLen {
-		maxLen = len(bv)
-	}
-	for i := 0; i < maxLen; i++ {
-		ai := 0
-		bi := 0
-		if i < len(av) {
-			ai = av[i]
-		}
-		if i < len(bv) {
-			bi = bv[i]
-		}
-		if ai > bi {
-			return 1, true
-		}
-		if ai < bi {
-			return -1, true
-		}
-	}
-	return 0, true
-}
-
-func parseVersionParts(v string) ([]int, bool) {
-	s := strings.TrimSpace(v)
-	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
-	if s == "" {
-		return nil, false
-	}
-
-	raw := strings.Split(s, ".")
-	parts := make([]int, 0, len(raw))
-	for _, seg := range raw {
-		if seg == "" {
-			return nil, false
-		}
-		n, ok := leadingInt(seg)
-		if !ok {
-			return nil, false
-		}
-		parts = append(parts, n)
-	}
-	return parts, true
-}
-
-func leadingInt(s string) (int, bool) {
-	var b strings.Builder
-	for _, r := range s {
-		if r < '0' || r > '9' {
-			break
-		}
-		b.WriteRune(r)
-	}
-	if b.Len() == 0 {
-		return 0, false
-	}
-	n, err := strconv.Atoi(b.String())
-	if err != nil {
-		return 0, false
-	}
-	return n, true
-}
-
 func ensureTailscaleAvailable() error {
 	cmd := exec.Command("tailscale", "version")
 	out, err := cmd.CombinedOutput()
@@ -1530,7 +2229,7 @@ func ensureTailscaleAvailable() error {
 
 	fmt.Fprintln(os.Stderr, colorize("Упозорење: Tailscale није доступан. Без Tailscale-а SSH ка gitcrn неће радити.", ansiYellow, stderrColor))
 
-	install, askErr := promptYesNo(os.Stderr, os.Stdin, "Да ли желиш упутство за инсталацију Tailscale-а? [y/N]: ")
+	install, askErr := promptYesNo(os.Stderr, stdin, "Да ли желиш упутство за инсталацију Tailscale-а? [y/N]: ")
 	if askErr == nil && install {
 		printTailscaleInstallHint(os.Stderr)
 	}
@@ -1538,11 +2237,10 @@ func ensureTailscaleAvailable() error {
 	return errors.New("инсталирај Tailscale па понови: gitcrn init --default")
 }
 
-func promptYesNo(w io.Writer, r io.Reader, prompt string) (bool, error) {
+func promptYesNo(w io.Writer, r *bufio.Reader, prompt string) (bool, error) {
 	fmt.Fprint(w, prompt)
 
-	reader := bufio.NewReader(r)
-	line, err := reader.ReadString('\n')
+	line, err := r.ReadString('\n')
 	if err != nil && !errors.Is(err, io.EOF) {
 		return false, err
 	}
@@ -1568,62 +2266,94 @@ func printTailscaleInstallHint(w io.Writer) {
 	}
 }
 
-func buildRepoURL(input string) (string, error) {
-	s := strings.TrimSpace(input)
-	if s == "" {
-		return "", errors.New("repo не сме бити празан")
+// resolveRepoURL picks the SSH or HTTPS form from the protocol config key;
+// --https always wins.
+func resolveRepoURL(cfg appConfig, input string, forceHTTPS bool) (string, error) {
+	if forceHTTPS || cfg.Protocol == "https" {
+		return buildHTTPSRepoURL(cfg.ServerURL, input)
 	}
+	return buildRepoURL(input)
+}
 
-	if strings.Contains(s, "://") {
-		return "", errors.New("користи owner/repo формат, не пун URL")
+func buildRepoURL(input string) (string, error) {
+	owner, repo, err := normalizeRepoSpec(input)
+	if err != nil {
+		return "", err
 	}
+	return fmt.Sprintf("%s:%s/%s.git", defaultHostAlias, owner, repo), nil
+}
 
-	if strings.HasPrefix(s, defaultHostAlias+":") {
-		s = strings.TrimPrefix(s, defaultHostAlias+":")
+func buildHTTPSRepoURL(serverURL, input string) (string, error) {
+	owner, repo, err := normalizeRepoSpec(input)
+	if err != nil {
+		return "", err
 	}
-
-	s = strings.TrimSuffix(s, ".git")
-	s = strings.TrimPrefix(s, "/")
-
-	parts := strings.Split(s, "/")
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return "", errors.New("repo мора бити у owner/repo формату")
+	base := strings.TrimRight(strings.TrimSpace(serverURL), "/")
+	if base == "" {
+		base = defaultServerURL
 	}
-
-	return fmt.Sprintf("%s:%s/%s.git", defaultHostAlias, parts[0], parts[1]), nil
+	return fmt.Sprintf("%s/%s/%s.git", base, owner, repo), nil
 }
 
-func upsertSSHConfig(alias, host, user string, port int) (string, bool, error) {
-	configPath, err := sshConfigPath()
-	if err != nil {
-		return "", false, err
+// resolveOwnerRepo uses the explicit argument when given and otherwise infers
+// owner/repo from the gitcrn (then origin) remote of the current checkout.
+func resolveOwnerRepo(arg, serverURL string) (owner, repo string, err error) {
+	if strings.TrimSpace(arg) != "" {
+		return normalizeRepoSpec(arg)
 	}
+	return inferOwnerRepo(serverURL)
+}
 
-	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
-		return "", false, fmt.Errorf("креирање .ssh директоријума: %w", err)
+func inferOwnerRepo(serverURL string) (owner, repo string, err error) {
+	for _, remote := range []string{defaultHostAlias, "origin"} {
+		raw := commandOutput("git", "remote", "get-url", remote)
+		if raw == "" {
+			continue
+		}
+		if owner, repo, err := parseRemoteURL(raw, serverURL); err == nil {
+			return owner, repo, nil
+		}
 	}
+	return "", "", errors.New("не могу да одредим owner/repo из gitcrn или origin remote-а. Наведи owner/repo")
+}
 
-	content := ""
-	data, err := os.ReadFile(configPath)
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return "", false, fmt.Errorf("читање %s: %w", configPath, err)
+// parseRemoteURL inverts buildRepoURL and buildHTTPSRepoURL. The server_url
+// prefix is stripped first so Gitea instances served from a subpath work.
+func parseRemoteURL(remoteURL, serverURL string) (owner, repo string, err error) {
+	s := strings.TrimSpace(remoteURL)
+	base := strings.TrimRight(strings.TrimSpace(serverURL), "/")
+	if base != "" && strings.HasPrefix(s, base+"/") {
+		s = strings.TrimPrefix(s, base+"/")
 	}
-	if err == nil {
-		content = normalizeNewlines(string(data))
+	return normalizeRepoSpec(s)
+}
+
+// normalizeRepoSpec accepts owner/repo, gitcrn:owner/repo, scp-style
+// git@host:owner/repo and web URLs like https://host/owner/repo.git.
+func normalizeRepoSpec(input string) (owner, repo string, err error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return "", "", errors.New("repo не сме бити празан")
 	}
 
-	if hasExactSSHHostConfig(content, alias, host, user, port) {
-		return configPath, false, nil
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return "", "", fmt.Errorf("невалидан URL: %s", s)
+		}
+		s = u.Path
+	} else if i := strings.Index(s, ":"); i >= 0 && !strings.Contains(s[:i], "/") {
+		s = s[i+1:]
 	}
 
-	block := renderSSHHostBlock(alias, host, user, port)
-	updated := mergeSSHHostBlock(content, alias, block)
+	s = strings.Trim(s, "/")
+	s = strings.TrimSuffix(s, ".git")
 
-	if err := os.WriteFile(configPath, []byte(updated), 0o600); err != nil {
-		return "", false, fmt.Errorf("упис %s: %w", configPath, err)
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("repo мора бити у owner/repo формату")
 	}
-
-	return configPath, true, nil
+	return parts[0], parts[1], nil
 }
 
 func hasExactSSHHostConfig(content, alias, host, user string, port int) bool {
@@ -1682,62 +2412,6 @@ func sshConfigPath() (string, error) {
 	return filepath.Join(home, ".ssh", "config"), nil
 }
 
-func renderSSHHostBlock(alias, host, user string, port int) string {
-	return strings.Join([]string{
-		fmt.Sprintf("Host %s", alias),
-		fmt.Sprintf("    HostName %s", host),
-		fmt.Sprintf("    User %s", user),
-		fmt.Sprintf("    Port %d", port),
-	}, "\n")
-}
-
-func mergeSSHHostBlock(content, alias, block string) string {
-	trimmed := strings.TrimSpace(content)
-	if trimmed == "" {
-		return block + "\n"
-	}
-
-	lines := strings.Split(content, "\n")
-	var out []string
-	replaced := false
-
-	for i := 0; i < len(lines); {
-		curr := strings.TrimSpace(lines[i])
-		if patterns, ok := parseHostLine(curr); ok && hostPatternMatches(patterns, alias) {
-			if !replaced {
-				if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
-					out = append(out, "")
-				}
-				out = append(out, strings.Split(block, "\n")...)
-				replaced = true
-			}
-
-			i++
-			for i < len(lines) {
-				next := strings.TrimSpace(lines[i])
-				if _, nextIsHost := parseHostLine(next); nextIsHost {
-					break
-				}
-				i++
-			}
-			continue
-		}
-
-		out = append(out, lines[i])
-		i++
-	}
-
-	if !replaced {
-		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
-			out = append(out, "")
-		}
-		out = append(out, strings.Split(block, "\n")...)
-	}
-
-	result := strings.TrimRight(strings.Join(out, "\n"), "\n")
-	return result + "\n"
-}
-
 func parseHostLine(line string) ([]string, bool) {
 	fields := strings.Fields(line)
 	if len(fields) < 2 || !strings.EqualFold(fields[0], "Host") {
@@ -1759,9 +2433,70 @@ func normalizeNewlines(s string) string {
 	return strings.ReplaceAll(s, "\r\n", "\n")
 }
 
+// parseFlagsAnywhere lets flags follow positional arguments, e.g.
+// `gitcrn browse main.go:42 --print`. A lone "--" ends flag parsing.
+func parseFlagsAnywhere(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	rest := args
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return nil, err
+		}
+		rest = fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if rest[0] == "--" {
+			return append(positional, rest[1:]...), nil
+		}
+		positional = append(positional, rest[0])
+		rest = rest[1:]
+	}
+}
+
+// splitKnownFlags sets the flags of fs found in args and returns the rest in
+// order, for commands that pass unknown options through to git. A lone "--"
+// passes everything after it through.
+func splitKnownFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(rest, args[i+1:]...), nil
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			rest = append(rest, arg)
+			continue
+		}
+		if name == "h" || name == "help" {
+			return nil, flag.ErrHelp
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+				value = "true"
+			} else if i+1 < len(args) {
+				i++
+				value = args[i]
+			} else {
+				return nil, fmt.Errorf("опција --%s тражи вредност", name)
+			}
+		}
+		if err := fs.Set(name, value); err != nil {
+			return nil, fmt.Errorf("--%s: %w", name, err)
+		}
+	}
+	return rest, nil
+}
+
 func runGit(args ...string) error {
 	cmd := exec.Command("git", args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = humanOut()
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
@@ -1889,6 +2624,10 @@ func detectColor(file *os.File) bool {
 	if strings.EqualFold(os.Getenv("TERM"), "dumb") {
 		return false
 	}
+	return isTerminal(file)
+}
+
+func isTerminal(file *os.File) bool {
 	info, err := file.Stat()
 	if err != nil {
 		return false
@@ -1903,6 +2642,28 @@ func colorize(msg, ansi string, enabled bool) string {
 	return ansi + msg + ansiReset
 }
 
+type versionInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	Authors   string `json:"authors"`
+	URL       string `json:"url"`
+}
+
+func currentVersionInfo() versionInfo {
+	return versionInfo{
+		Name:      appName,
+		Version:   version,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Authors:   creatorNames,
+		URL:       projectURL,
+	}
+}
+
 func printVersion(w io.Writer) {
 	fmt.Fprintf(w, `%s %s
 Направио: %s
@@ -1920,18 +2681,36 @@ func printRootUsage(w io.Writer) {
   %s create repo owner/repo
   %s make repo owner/repo
   %s repo create owner/repo
+  %s repo view [owner/repo]
+  %s repo list [owner]
+  %s config list|path
+  %s endpoint list|probe|use
+  %s browse [owner/repo] [path[:line]] [--issues|--pulls|--releases|--settings|--commit <sha>] [--print]
+  %s api [method] <path> [-f key=value] [--paginate] [-q <filter>]
+  %s issue list|view|create|comment|close|reopen|edit
+  %s pr create|list|view|checkout|merge|diff
+  %s release create|list|download|delete
+  %s self-update [--version vX.Y.Z] [--rollback]
   %s doctor
   %s make --push --pull
   %s remake -pp
   %s -pp
   %s init --default
   %s init --custom --host <host> --port <port> --user <user>
-  %s clone owner/repo [directory]
+  %s init --tailscale <машина> [--magicdns]
+  %s uninit [--all] [--dry-run]
+  %s clone [--https] owner/repo [directory]
   %s push
   %s pull
-  %s add owner/repo
+  %s add [--https] owner/repo
   %s -v | --version
 
+Глобалне опције:
+  --json                    JSON излаз на stdout (поруке иду на stderr)
+  --format json|table|plain формат излаза за list/view/doctor/version/config
+
+Излазни кодови: 0 успех, 1 грешка (и погрешни аргументи).
+
 Примери:
   %s generate config
   %s -gc --force
@@ -1939,47 +2718,88 @@ func printRootUsage(w io.Writer) {
   %s create repo vltc/mojrepo --private --clone
   %s make repo vltc/mojrepo --private --clone
   %s repo create crnbg/platform --public
-  %s doctor
+  %s repo view
+  %s browse cmd/gitcrn/main.go:42
+  %s browse vltc/kapri --issues --print
+  %s api repos/{owner}/{repo}/issues --paginate -q '.[].title'
+  %s issue list --label bug --assignee vltc
+  %s issue create --title "Пада build" --label bug
+  %s pr create --fill
+  %s pr merge 7 --style squash --delete-branch
+  %s release create v0.6.0 'dist/*' --prerelease
+  %s doctor --json
+  %s repo list --format plain
+  %s endpoint probe
   %s make --push --pull
   %s remake --push
   %s -pp
   %s init --default
   %s init --custom --host 100.91.132.35 --port 222 --user git
+  %s init --tailscale gitea
+  %s init --default --dry-run
+  %s uninit --dry-run --all
   %s clone vltc/kapri
+  %s clone --https https://gitcrn.example/vltc/kapri
   %s push
   %s pull
   %s add vltc/crnbg
-`, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName)
+`, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName, appName)
 }
 
 func printInitUsage(w io.Writer) {
 	fmt.Fprintf(w, `Коришћење:
   %s init --default
   %s init --custom --host <host> --port <port> --user <user>
-`, appName, appName)
+  %s init --tailscale <машина> [--magicdns] [--port <port>] [--user <user>]
+
+--tailscale налази сервер по имену у tailscale status и у Host блок и
+config.toml (ssh_host, server_url, tailscale_node) уписује његову 100.x
+адресу, или MagicDNS име уз --magicdns.
+
+Host блок је између "# BEGIN gitcrn" и "# END gitcrn". init мења само
+HostName, User и Port; остале линије у блоку (IdentityFile, ProxyJump...)
+остају. Пре сваке промене ~/.ssh/config се копира у config.gitcrn-<време>.bak.
+--dry-run само испише unified diff, ништа не мења.
+`, appName, appName, appName)
 }
 
 func printCloneUsage(w io.Writer) {
 	fmt.Fprintf(w, `Коришћење:
-  %s clone owner/repo [directory]
-`, appName)
+  %s clone [--https] owner/repo [directory] [git clone опције]
+
+owner/repo може бити и web URL (https://host/owner/repo) или scp облик (git@host:owner/repo).
+Опције после owner/repo које gitcrn не зна иду у git clone
+(%s clone owner/repo dir --depth 1); после -- иде све, и --https.
+`, appName, appName)
 }
 
 func printPushUsage(w io.Writer) {
 	fmt.Fprintf(w, `Коришћење:
-  %s push
+  %s push [--timeout 60s] [--required gitcrn,origin] [--script]
+
+Са .gitcrn.toml у корену репоа: git add ., commit са message (ако има
+измена) и push гране на све push_remotes истовремено, сваки са својим
+timeout-ом (push_timeout, подразумевано 60s). Пад једног remote-а не
+зауставља остале; на крају се испише табела remote, ref, old..new,
+резултат. Излазни код је 1 само ако је пао обавезан remote
+(required_remotes, подразумевано сви). Без .gitcrn.toml (или са --script)
+покреће push.sh/push.ps1 из тренутног директоријума.
 `, appName)
 }
 
 func printPullUsage(w io.Writer) {
 	fmt.Fprintf(w, `Коришћење:
-  %s pull
+  %s pull [--script]
+
+Са .gitcrn.toml у корену репоа: git pull гране са сваког pull_remotes
+редом, уз статус по remote-у; после првог неуспеха стаје. Без .gitcrn.toml
+(или са --script) покреће pull.sh/pull.ps1 из тренутног директоријума.
 `, appName)
 }
 
 func printAddUsage(w io.Writer) {
 	fmt.Fprintf(w, `Коришћење:
-  %s add owner/repo
+  %s add [--https] owner/repo
 `, appName)
 }
 
@@ -1991,8 +2811,15 @@ func printRemoteUsage(w io.Writer) {
 
 func printDoctorUsage(w io.Writer) {
 	fmt.Fprintf(w, `Коришћење:
-  %s doctor
-`, appName)
+  %s doctor [--strict] [--only ssh,git,tailscale,config,security,net] [--timeout 5s] [--json|--format plain]
+  %s doctor --fix [--yes]
+
+Сваки налаз има id, ниво (ok, warn, fail), поруку и предлог поправке.
+Излазни код је 1 ако има грешака (са --strict и ако има упозорења).
+--fix нуди поправке једну по једну, --yes их примењује без питања.
+security тражи лабаве дозволе, token у shell историји или у репоу и Host *
+блокове који мењају gitcrn подешавања.
+`, appName, appName)
 }
 
 func printGenerateUsage(w io.Writer) {
@@ -2027,7 +2854,12 @@ func printCreateUsage(w io.Writer) {
 func printRepoUsage(w io.Writer) {
 	fmt.Fprintf(w, `Коришћење:
   %s repo create owner/repo [--private|--public] [--desc "..."] [--default-branch main] [--clone]
-`, appName)
+  %s repo view [owner/repo]
+  %s repo list [owner] [--limit 50]
+
+Без owner/repo, repo се чита из gitcrn (па origin) remote-а тренутног репоа.
+Без owner, list приказује репое пријављеног корисника.
+`, appName, appName, appName)
 }
 
 func printCreateRepoUsage(w io.Writer) {
@@ -2046,5 +2878,25 @@ func printMakeUsage(w io.Writer) {
   %s remake --push --pull
   %s remake -pp
   %s -pp
-`, appName, appName, appName, appName, appName, appName)
+
+make упише грану, commit поруку и remote-е у .gitcrn.toml у корену репоа,
+а push и pull их одатле читају. remake мења постојеће вредности; make
+одбија ако су већ подешене. Са --script уместо тога извезе push.sh/pull.sh
+(push.ps1/pull.ps1 на Windows-у) у тренутни директоријум.
+
+Без флагова make пита за вредности (подразумеване су из .gitcrn.toml ако
+постоји). Одговори могу да се пошаљу и кроз stdin (по један ред); ако stdin
+стане пре краја, make јави грешку уместо да тихо узме подразумевано.
+
+  --branch <грана>          грана
+  --message <порука>        commit порука за push
+  --push-remotes <r1,r2>    remote-и за push
+  --pull-remotes <r1,r2>    remote-и за pull
+  --yes                     за све што није задато узми подразумевано
+                            (тренутна грана, ❄, remote-и из git remote -v)
+  --script                  извези скрипте уместо .gitcrn.toml
+
+Пример за скрипте:
+  %s make -pp --branch main --message "sync" --push-remotes gitcrn,origin --pull-remotes gitcrn
+`, appName, appName, appName, appName, appName, appName, appName)
 }
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"bufio"
+	"errors"
+	"flag"
+	"io"
 	"strings"
 	"testing"
 )
@@ -33,9 +37,14 @@ func TestBuildRepoURL(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "reject https",
-			input:   "https://example.com/vltc/kapri.git",
-			wantErr: true,
+			name:  "normalize https web url",
+			input: "https://example.com/vltc/kapri.git",
+			want:  "gitcrn:vltc/kapri.git",
+		},
+		{
+			name:  "normalize scp style",
+			input: "git@example.com:vltc/kapri",
+			want:  "gitcrn:vltc/kapri.git",
 		},
 		{
 			name:    "reject missing repo",
@@ -68,6 +77,72 @@ func TestBuildRepoURL(t *testing.T) {
 	}
 }
 
+func TestResolveRepoURLUsesGivenConfig(t *testing.T) {
+	// runClone passes the config after autoSelectEndpoint, so the fallback
+	// endpoint's server_url must end up in the clone URL.
+	cfg := appConfig{ServerURL: "http://100.64.0.9:5000", Protocol: "https"}
+	got, err := resolveRepoURL(cfg, "vltc/kapri", false)
+	if err != nil || got != "http://100.64.0.9:5000/vltc/kapri.git" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	cfg.Protocol = "ssh"
+	if got, _ := resolveRepoURL(cfg, "vltc/kapri", false); got != "gitcrn:vltc/kapri.git" {
+		t.Fatalf("ssh form: got %q", got)
+	}
+	if got, _ := resolveRepoURL(cfg, "vltc/kapri", true); got != "http://100.64.0.9:5000/vltc/kapri.git" {
+		t.Fatalf("--https form: got %q", got)
+	}
+}
+
+func TestBuildHTTPSRepoURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "vltc/kapri", want: "http://gitcrn.local:5000/vltc/kapri.git"},
+		{input: "https://gitcrn.local/vltc/kapri", want: "http://gitcrn.local:5000/vltc/kapri.git"},
+		{input: "git@gitcrn.local:vltc/kapri.git", want: "http://gitcrn.local:5000/vltc/kapri.git"},
+		{input: "gitcrn:vltc/kapri.git", want: "http://gitcrn.local:5000/vltc/kapri.git"},
+	}
+
+	for _, tc := range tests {
+		got, err := buildHTTPSRepoURL("http://gitcrn.local:5000/", tc.input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.input, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.input, got, tc.want)
+		}
+	}
+
+	if _, err := buildHTTPSRepoURL("http://gitcrn.local:5000", "https://gitcrn.local/vltc"); err == nil {
+		t.Fatalf("expected error for url without repo")
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{remote: "gitcrn:vltc/kapri.git", want: "vltc/kapri"},
+		{remote: "git@github.com:crnobog69/gitcrn-cli-bin.git", want: "crnobog69/gitcrn-cli-bin"},
+		{remote: "ssh://git@100.91.132.35:222/vltc/kapri.git", want: "vltc/kapri"},
+		{remote: "http://100.91.132.35:5000/vltc/kapri.git", want: "vltc/kapri"},
+		{remote: "https://git.example.com/gitea/vltc/kapri.git", want: "vltc/kapri"},
+	}
+
+	for _, tc := range tests {
+		owner, repo, err := parseRemoteURL(tc.remote, "https://git.example.com/gitea/")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.remote, err)
+		}
+		if got := owner + "/" + repo; got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.remote, got, tc.want)
+		}
+	}
+}
+
 func TestMergeSSHHostBlockReplaceAndAppend(t *testing.T) {
 	block := renderSSHHostBlock("gitcrn", "100.91.132.35", "git", 222)
 
@@ -122,31 +197,6 @@ func TestHasExactSSHHostConfig(t *testing.T) {
 	}
 }
 
-func TestCompareSemver(t *testing.T) {
-	tests := []struct {
-		a      string
-		b      string
-		want   int
-		ok     bool
-		testID string
-	}{
-		{a: "v0.2.0", b: "v0.1.9", want: 1, ok: true, testID: "newer"},
-		{a: "0.1.0", b: "v0.1.0", want: 0, ok: true, testID: "same"},
-		{a: "v1.0.0", b: "dev", want: 0, ok: false, testID: "non-semver-local"},
-		{a: "v0.1.0", b: "v0.2.0", want: -1, ok: true, testID: "older"},
-	}
-
-	for _, tc := range tests {
-		got, ok := compareSemver(tc.a, tc.b)
-		if ok != tc.ok {
-			t.Fatalf("%s: ok=%v want %v", tc.testID, ok, tc.ok)
-		}
-		if ok && got != tc.want {
-			t.Fatalf("%s: got=%d want=%d", tc.testID, got, tc.want)
-		}
-	}
-}
-
 func TestParseRemoteNames(t *testing.T) {
 	out := strings.Join([]string{
 		"origin\thttps://example.com/repo.git (fetch)",
@@ -172,6 +222,28 @@ func TestParseRemoteList(t *testing.T) {
 	}
 }
 
+func TestPromptInputSharesReader(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("dev\n\nyes\n"))
+	var answers []string
+	for _, def := range []string{"main", "❄️"} {
+		v, err := promptInput(io.Discard, r, "x", def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		answers = append(answers, v)
+	}
+	ok, err := promptYesNo(io.Discard, r, "?")
+	if err != nil || !ok {
+		t.Fatalf("third answer lost: %v, %v", ok, err)
+	}
+	if strings.Join(answers, ",") != "dev,❄️" {
+		t.Fatalf("unexpected answers: %v", answers)
+	}
+	if _, err := promptInput(io.Discard, r, "x", "main"); !errors.Is(err, io.EOF) {
+		t.Fatalf("exhausted input must not fall back to the default: %v", err)
+	}
+}
+
 func TestParseOwnerRepo(t *testing.T) {
 	owner, repo, err := parseOwnerRepo("vltc/kapri")
 	if err != nil {
@@ -246,3 +318,22 @@ func TestShouldCheckUpdates(t *testing.T) {
 		t.Fatalf("env flag should disable update checks")
 	}
 }
+
+func TestSplitKnownFlags(t *testing.T) {
+	fs := newFlagSet("clone")
+	useHTTPS := fs.Bool("https", false, "")
+
+	rest, err := splitKnownFlags(fs, []string{"dir", "--depth", "1", "--https", "--branch=dev", "--", "--https"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*useHTTPS {
+		t.Fatal("--https after owner/repo must still be read")
+	}
+	if strings.Join(rest, " ") != "dir --depth 1 --branch=dev --https" {
+		t.Fatalf("unexpected git args: %q", rest)
+	}
+	if _, err := splitKnownFlags(fs, []string{"--help"}); !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("expected ErrHelp, got %v", err)
+	}
+}
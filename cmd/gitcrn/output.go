@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	formatJSON  = "json"
+	formatTable = "table"
+	formatPlain = "plain"
+)
+
+// outputFormat is set from the global --json / --format flags. Empty means
+// the command's usual human output.
+var outputFormat string
+
+// parseGlobalOutputFlags removes --json and --format from the command path:
+// before the command and between subcommand words, e.g. `gitcrn --json
+// doctor` or `gitcrn config list --json`. Scanning stops at the first other
+// flag; from there the subcommand's FlagSet (newFlagSet) parses --json and
+// --format itself, so a value such as `--body --json` stays the body.
+func parseGlobalOutputFlags(args []string) ([]string, string, error) {
+	format := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--json":
+			format = formatJSON
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("--format тражи вредност (json, table или plain)")
+			}
+			i++
+			v, err := checkOutputFormat(args[i])
+			if err != nil {
+				return nil, "", err
+			}
+			format = v
+		case strings.HasPrefix(arg, "--format="):
+			v, err := checkOutputFormat(strings.TrimPrefix(arg, "--format="))
+			if err != nil {
+				return nil, "", err
+			}
+			format = v
+		case strings.HasPrefix(arg, "-"):
+			rest := append([]string(nil), args[:i]...)
+			return append(stripOutputFlags(rest), args[i:]...), format, nil
+		}
+	}
+	return stripOutputFlags(args), format, nil
+}
+
+// stripOutputFlags drops --json/--format tokens already read from a command
+// path that has no other flags.
+func stripOutputFlags(args []string) []string {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--json", strings.HasPrefix(args[i], "--format="):
+		case args[i] == "--format":
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest
+}
+
+// mentionsMachineOutput reports whether args may ask for JSON or plain
+// output further on. It only keeps the update notice out of such runs, so
+// an occasional false positive just hides the notice.
+func mentionsMachineOutput(args []string) bool {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return false
+		case arg == "--json", arg == "-json":
+			return true
+		case strings.HasPrefix(arg, "--format=") || strings.HasPrefix(arg, "-format="):
+			return true
+		case (arg == "--format" || arg == "-format") && i+1 < len(args):
+			return true
+		}
+	}
+	return false
+}
+
+func checkOutputFormat(v string) (string, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	switch v {
+	case formatJSON, formatTable, formatPlain:
+		return v, nil
+	default:
+		return "", fmt.Errorf("непознат --format %q (json, table или plain)", v)
+	}
+}
+
+// setOutputFormat applies --json / --format given after the command.
+func setOutputFormat(v string) error {
+	format, err := checkOutputFormat(v)
+	if err != nil {
+		return err
+	}
+	outputFormat = format
+	if machineOutput() {
+		stdoutColor = false
+	}
+	return nil
+}
+
+// outputFormatFlag is --format, or --json when json is set.
+type outputFormatFlag struct{ json bool }
+
+func (f outputFormatFlag) String() string { return outputFormat }
+
+func (f outputFormatFlag) Set(v string) error {
+	if !f.json {
+		return setOutputFormat(v)
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil || !on {
+		return err
+	}
+	return setOutputFormat(formatJSON)
+}
+
+func (f outputFormatFlag) IsBoolFlag() bool { return f.json }
+
+// newFlagSet is the FlagSet every subcommand uses. It also accepts the
+// global --json and --format flags, so they work anywhere among the
+// subcommand's own flags.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(outputFormatFlag{json: true}, "json", "JSON излаз")
+	fs.Var(outputFormatFlag{}, "format", "json, table или plain")
+	return fs
+}
+
+func jsonOutput() bool {
+	return outputFormat == formatJSON
+}
+
+// machineOutput reports whether stdout is meant for scripts, in which case
+// colours, notices and progress text stay off stdout.
+func machineOutput() bool {
+	return outputFormat == formatJSON || outputFormat == formatPlain
+}
+
+// humanOut is where status messages go: stdout normally, stderr when stdout
+// carries JSON or plain output.
+func humanOut() io.Writer {
+	if machineOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// tableWriter prints aligned columns with a header, or bare tab-separated
+// rows without a header for --format plain.
+type tableWriter struct {
+	w     io.Writer
+	tw    *tabwriter.Writer
+	plain bool
+}
+
+func newTableWriter(w io.Writer, headers ...string) *tableWriter {
+	t := &tableWriter{w: w, plain: outputFormat == formatPlain}
+	if !t.plain {
+		t.tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(t.tw, strings.Join(headers, "\t"))
+	}
+	return t
+}
+
+func (t *tableWriter) Row(cols ...string) {
+	if t.plain {
+		for i, c := range cols {
+			cols[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(c)
+		}
+		fmt.Fprintln(t.w, strings.Join(cols, "\t"))
+		return
+	}
+	fmt.Fprintln(t.tw, strings.Join(cols, "\t"))
+}
+
+// Cell shortens s for the aligned table; plain output keeps it whole.
+func (t *tableWriter) Cell(s string, max int) string {
+	if t.plain {
+		return s
+	}
+	return truncate(s, max)
+}
+
+func (t *tableWriter) Flush() {
+	if t.tw != nil {
+		t.tw.Flush()
+	}
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGlobalOutputFlags(t *testing.T) {
+	tests := []struct {
+		args   []string
+		rest   []string
+		format string
+	}{
+		{[]string{"doctor"}, []string{"doctor"}, ""},
+		{[]string{"--json", "version"}, []string{"version"}, formatJSON},
+		{[]string{"issue", "list", "--json", "--limit", "5"}, []string{"issue", "list", "--limit", "5"}, formatJSON},
+		{[]string{"repo", "list", "--format", "plain"}, []string{"repo", "list"}, formatPlain},
+		{[]string{"--format=TABLE", "pr", "list"}, []string{"pr", "list"}, formatTable},
+		{[]string{"api", "--", "--json"}, []string{"api", "--", "--json"}, ""},
+		{[]string{"config", "list", "--json"}, []string{"config", "list"}, formatJSON},
+		{[]string{"issue", "--json", "list"}, []string{"issue", "list"}, formatJSON},
+		// From the first other flag on, the subcommand's FlagSet decides.
+		{[]string{"issue", "create", "--body", "--json"}, []string{"issue", "create", "--body", "--json"}, ""},
+		{[]string{"api", "-f", "--format", "--json"}, []string{"api", "-f", "--format", "--json"}, ""},
+		{[]string{"-v", "--json"}, []string{"-v", "--json"}, ""},
+	}
+	for _, tc := range tests {
+		rest, format, err := parseGlobalOutputFlags(tc.args)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.args, err)
+		}
+		if !reflect.DeepEqual(rest, tc.rest) || format != tc.format {
+			t.Fatalf("%v: got %v %q, want %v %q", tc.args, rest, format, tc.rest, tc.format)
+		}
+	}
+
+	if _, _, err := parseGlobalOutputFlags([]string{"doctor", "--format", "yaml"}); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+	if _, _, err := parseGlobalOutputFlags([]string{"doctor", "--format"}); err == nil {
+		t.Fatalf("expected error for missing format value")
+	}
+}
+
+func TestNewFlagSetOutputFlags(t *testing.T) {
+	defer func(old string, color bool) { outputFormat, stdoutColor = old, color }(outputFormat, stdoutColor)
+
+	parse := func(args ...string) (string, bool) {
+		outputFormat = ""
+		fs := newFlagSet("test")
+		body := fs.String("body", "", "")
+		yes := fs.Bool("yes", false, "")
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return *body, *yes
+	}
+
+	if body, _ := parse("--body", "--json"); body != "--json" || outputFormat != "" {
+		t.Fatalf("--json as a value must stay the body: %q, format %q", body, outputFormat)
+	}
+	if _, yes := parse("--yes", "--json"); !yes || outputFormat != formatJSON {
+		t.Fatalf("--json after a bool flag must switch to JSON: format %q", outputFormat)
+	}
+	if body, _ := parse("--body", "x", "--format", "plain"); body != "x" || outputFormat != formatPlain {
+		t.Fatalf("unexpected --format handling: %q, %q", body, outputFormat)
+	}
+	if err := newFlagSet("test").Parse([]string{"--format", "yaml"}); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestRunVersionJSON(t *testing.T) {
+	defer func(old string) { outputFormat = old }(outputFormat)
+
+	// `gitcrn -v --json`: the global scan stops at -v and leaves --json to
+	// the version FlagSet.
+	rest, format, err := parseGlobalOutputFlags([]string{"-v", "--json"})
+	if err != nil || format != "" || !reflect.DeepEqual(rest, []string{"-v", "--json"}) {
+		t.Fatalf("unexpected scan: %v %q %v", rest, format, err)
+	}
+	outputFormat = ""
+	var buf bytes.Buffer
+	if err := runVersion(&buf, rest[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(buf.String()), "{") || !strings.Contains(buf.String(), `"version"`) {
+		t.Fatalf("expected JSON, got:\n%s", buf.String())
+	}
+}
+
+func TestTableWriterPlain(t *testing.T) {
+	defer func(old string) { outputFormat = old }(outputFormat)
+
+	outputFormat = formatPlain
+	var buf bytes.Buffer
+	tw := newTableWriter(&buf, "A", "B")
+	tw.Row("x", tw.Cell("дуг\tнаслов са табом", 5))
+	tw.Flush()
+	if got := buf.String(); got != "x\tдуг наслов са табом\n" {
+		t.Fatalf("unexpected plain output: %q", got)
+	}
+
+	outputFormat = ""
+	buf.Reset()
+	tw = newTableWriter(&buf, "A", "B")
+	tw.Row("x", "y")
+	tw.Flush()
+	if got := buf.String(); got != "A  B\nx  y\n" {
+		t.Fatalf("unexpected table output: %q", got)
+	}
+}
+
+func TestMaskToken(t *testing.T) {
+	if got := maskToken(""); got != "" {
+		t.Fatalf("empty token: %q", got)
+	}
+	if got := maskToken("abc"); got != "***" {
+		t.Fatalf("short token: %q", got)
+	}
+	if got := maskToken("0123456789abcdef"); got != "********cdef" {
+		t.Fatalf("long token: %q", got)
+	}
+}
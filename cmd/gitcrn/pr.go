@@ -0,0 +1,575 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type giteaPRBranch struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	Sha   string `json:"sha"`
+}
+
+type giteaPull struct {
+	Number    int64         `json:"number"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	State     string        `json:"state"`
+	HTMLURL   string        `json:"html_url"`
+	User      giteaUser     `json:"user"`
+	Labels    []giteaLabel  `json:"labels"`
+	Assignees []giteaUser   `json:"assignees"`
+	Head      giteaPRBranch `json:"head"`
+	Base      giteaPRBranch `json:"base"`
+	Mergeable bool          `json:"mergeable"`
+	Merged    bool          `json:"merged"`
+	Comments  int           `json:"comments"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	MergedAt  *time.Time    `json:"merged_at"`
+}
+
+// pullJSON is the stable shape printed by --json.
+type pullJSON struct {
+	Number    int64      `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	Author    string     `json:"author"`
+	Head      string     `json:"head"`
+	Base      string     `json:"base"`
+	Labels    []string   `json:"labels"`
+	Assignees []string   `json:"assignees"`
+	Mergeable bool       `json:"mergeable"`
+	Merged    bool       `json:"merged"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
+}
+
+var prMergeStyles = []string{"merge", "rebase", "rebase-merge", "squash"}
+
+func runPR(args []string) error {
+	if len(args) < 1 {
+		printPRUsage(os.Stdout)
+		return nil
+	}
+
+	switch args[0] {
+	case "create", "new":
+		return runPRCreate(args[1:])
+	case "list", "ls":
+		return runPRList(args[1:])
+	case "view":
+		return runPRView(args[1:])
+	case "checkout", "co":
+		return runPRCheckout(args[1:])
+	case "merge":
+		return runPRMerge(args[1:])
+	case "diff":
+		return runPRDiff(args[1:])
+	case "-h", "--help", "help":
+		printPRUsage(os.Stdout)
+		return nil
+	default:
+		printPRUsage(os.Stderr)
+		return fmt.Errorf("неподржана pr подкоманда: %s", args[0])
+	}
+}
+
+func runPRCreate(args []string) error {
+	fs := newFlagSet("pr create")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	head := fs.String("head", "", "Грана са изменама (подразумевано тренутна)")
+	base := fs.String("base", "", "Циљна грана (подразумевано default грана репоа)")
+	title := fs.String("title", "", "Наслов")
+	body := fs.String("body", "", "Опис")
+	bodyFile := fs.String("body-file", "", "Опис из фајла или - за stdin")
+	labels := fs.String("label", "", "Лабеле (зарез)")
+	assignees := fs.String("assignee", "", "Додељени корисници (зарез)")
+	fill := fs.Bool("fill", false, "Користи наслов и опис из commit-а без editor-а")
+
+	if err := fs.Parse(args); err != nil {
+		return prFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		printPRUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	headBranch := strings.TrimSpace(*head)
+	if headBranch == "" {
+		headBranch = strings.TrimSpace(commandOutput("git", "branch", "--show-current"))
+	}
+	if headBranch == "" {
+		return errors.New("не могу да одредим тренутну грану. Користи --head")
+	}
+
+	baseBranch := strings.TrimSpace(*base)
+	if baseBranch == "" {
+		var r giteaRepo
+		if err := client.doJSON(http.MethodGet, repoPath(owner, repoName), nil, &r); err != nil {
+			return fmt.Errorf("читање default гране: %w", err)
+		}
+		baseBranch = fallback(r.DefaultBranch, "main")
+	}
+	if baseBranch == headBranch {
+		return fmt.Errorf("head и base су иста грана (%s)", headBranch)
+	}
+
+	finalTitle := strings.TrimSpace(*title)
+	finalBody := *body
+	if *bodyFile != "" {
+		finalBody, err = resolveBody("", *bodyFile, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	if finalTitle == "" {
+		commits := commitsBetween(baseBranch, headBranch)
+		fillTitle, fillBody := prFillFromCommits(headBranch, commits)
+		if finalBody == "" {
+			finalBody = fillBody
+		}
+
+		if *fill || !isTerminal(os.Stdin) {
+			finalTitle = fillTitle
+		} else {
+			finalTitle, finalBody, err = resolveTitleBody("", "", "", fillTitle+"\n\n"+finalBody+"\n")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if strings.TrimSpace(finalTitle) == "" {
+		return errors.New("наслов је празан, прекидам")
+	}
+
+	payload := map[string]any{
+		"head":  headBranch,
+		"base":  baseBranch,
+		"title": finalTitle,
+		"body":  strings.TrimSpace(finalBody),
+	}
+	if names := splitList(*labels); len(names) > 0 {
+		ids, err := resolveLabelIDs(client, owner, repoName, names)
+		if err != nil {
+			return err
+		}
+		payload["labels"] = ids
+	}
+	if logins := splitList(*assignees); len(logins) > 0 {
+		payload["assignees"] = logins
+	}
+
+	var created giteaPull
+	if err := client.doJSON(http.MethodPost, repoPath(owner, repoName)+"/pulls", payload, &created); err != nil {
+		var apiErr *giteaAPIError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusConflict {
+			return fmt.Errorf("PR за %s -> %s већ постоји: %s", headBranch, baseBranch, apiErr.Message)
+		}
+		if isNotFound(err) {
+			return fmt.Errorf("грана %s не постоји на серверу. Прво push-уј: git push %s %s", headBranch, prRemote(), headBranch)
+		}
+		return fmt.Errorf("креирање PR-а није успело: %w", err)
+	}
+
+	fmt.Fprintln(humanOut(), colorize(fmt.Sprintf("PR #%d креиран: %s (%s -> %s)", created.Number, created.Title, headBranch, baseBranch), ansiGreen, stdoutColor))
+	fmt.Fprintln(humanOut(), created.HTMLURL)
+	return nil
+}
+
+type commitInfo struct {
+	Subject string
+	Body    string
+}
+
+func commitsBetween(base, head string) []commitInfo {
+	baseRef := base
+	for _, remote := range []string{defaultHostAlias, "origin"} {
+		ref := remote + "/" + base
+		if commandOutput("git", "rev-parse", "--verify", "--quiet", ref) != "" {
+			baseRef = ref
+			break
+		}
+	}
+	out := commandOutput("git", "log", "--reverse", "--format=%s%x1f%b%x1e", baseRef+".."+head)
+	return parseCommitLog(out)
+}
+
+func parseCommitLog(out string) []commitInfo {
+	var commits []commitInfo
+	for _, rec := range strings.Split(out, "\x1e") {
+		rec = strings.TrimSpace(rec)
+		if rec == "" {
+			continue
+		}
+		subject, body, _ := strings.Cut(rec, "\x1f")
+		commits = append(commits, commitInfo{Subject: strings.TrimSpace(subject), Body: strings.TrimSpace(body)})
+	}
+	return commits
+}
+
+// prFillFromCommits mirrors what most forges do: a single commit supplies
+// title and body, several commits give a title from the branch name and a
+// bullet list of subjects.
+func prFillFromCommits(branch string, commits []commitInfo) (title, body string) {
+	switch len(commits) {
+	case 0:
+		return humanizeBranch(branch), ""
+	case 1:
+		return commits[0].Subject, commits[0].Body
+	}
+
+	var sb strings.Builder
+	for _, c := range commits {
+		sb.WriteString("- " + c.Subject + "\n")
+	}
+	return humanizeBranch(branch), strings.TrimRight(sb.String(), "\n")
+}
+
+func humanizeBranch(branch string) string {
+	name := branch
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(name)
+	if name == "" {
+		return branch
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+func runPRList(args []string) error {
+	fs := newFlagSet("pr list")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	state := fs.String("state", "open", "open, closed или all")
+	labels := fs.String("label", "", "Лабеле (зарез)")
+	limit := fs.Int("limit", 30, "Максималан број резултата")
+
+	if err := fs.Parse(args); err != nil {
+		return prFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		printPRUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+	switch *state {
+	case "open", "closed", "all":
+	default:
+		return fmt.Errorf("--state мора бити open, closed или all")
+	}
+	if *limit <= 0 {
+		return errors.New("--limit мора бити већи од 0")
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("state", *state)
+	if names := splitList(*labels); len(names) > 0 {
+		ids, err := resolveLabelIDs(client, owner, repoName, names)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			query.Add("labels", fmt.Sprint(id))
+		}
+	}
+
+	pulls, err := listPages[giteaPull](client, repoPath(owner, repoName)+"/pulls", query, *limit)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		out := make([]pullJSON, 0, len(pulls))
+		for _, p := range pulls {
+			out = append(out, toPullJSON(p))
+		}
+		return writeJSON(os.Stdout, out)
+	}
+
+	if len(pulls) == 0 {
+		fmt.Fprintln(os.Stderr, "Нема PR-ова за задате филтере.")
+		return nil
+	}
+	t := newTableWriter(os.Stdout, "#", "НАСЛОВ", "ГРАНЕ", "АУТОР", "СТАЊЕ")
+	for _, p := range pulls {
+		t.Row(strconv.FormatInt(p.Number, 10), t.Cell(p.Title, 60), p.Head.Ref+" -> "+p.Base.Ref, p.User.Login, pullState(p))
+	}
+	t.Flush()
+	return nil
+}
+
+func runPRView(args []string) error {
+	fs := newFlagSet("pr view")
+	repoFlag := fs.String("repo", "", "owner/repo")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return prFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+	p, err := getPull(client, owner, repoName, number)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		out := struct {
+			pullJSON
+			Body string `json:"body"`
+		}{toPullJSON(p), p.Body}
+		return writeJSON(os.Stdout, out)
+	}
+
+	fmt.Println(colorize(fmt.Sprintf("#%d %s", p.Number, p.Title), ansiCyan, stdoutColor))
+	fmt.Printf("Стање: %s · Аутор: %s · %s -> %s\n", pullState(p), fallback(p.User.Login, "?"), p.Head.Ref, p.Base.Ref)
+	if p.State == "open" {
+		if p.Mergeable {
+			fmt.Println(colorize("Може да се merge-ује", ansiGreen, stdoutColor))
+		} else {
+			fmt.Println(colorize("Има конфликте или чека проверу", ansiYellow, stdoutColor))
+		}
+	}
+	if names := labelNames(p.Labels); len(names) > 0 {
+		fmt.Printf("Лабеле: %s\n", strings.Join(names, ", "))
+	}
+	fmt.Printf("URL: %s\n", p.HTMLURL)
+	if strings.TrimSpace(p.Body) != "" {
+		fmt.Println()
+		fmt.Println(strings.TrimSpace(p.Body))
+	}
+	return nil
+}
+
+func runPRCheckout(args []string) error {
+	fs := newFlagSet("pr checkout")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	branch := fs.String("branch", "", "Име локалне гране")
+	remote := fs.String("remote", "", "Remote за fetch (подразумевано gitcrn, па origin)")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return prFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	localBranch := strings.TrimSpace(*branch)
+	if localBranch == "" {
+		localBranch = fmt.Sprintf("pr-%d", number)
+		if client, owner, repoName, err := repoClient(*repoFlag); err == nil {
+			if p, err := getPull(client, owner, repoName, number); err == nil && p.Head.Ref != "" {
+				localBranch = p.Head.Ref
+			}
+		}
+	}
+
+	fetchRemote := strings.TrimSpace(*remote)
+	if fetchRemote == "" {
+		fetchRemote = prRemote()
+	}
+
+	ref := fmt.Sprintf("refs/pull/%d/head", number)
+	current := strings.TrimSpace(commandOutput("git", "branch", "--show-current"))
+	if current == localBranch {
+		if err := runGit("fetch", fetchRemote, ref); err != nil {
+			return err
+		}
+		return runGit("merge", "--ff-only", "FETCH_HEAD")
+	}
+
+	if err := runGit("fetch", fetchRemote, ref+":"+localBranch); err != nil {
+		return err
+	}
+	return runGit("checkout", localBranch)
+}
+
+func runPRMerge(args []string) error {
+	fs := newFlagSet("pr merge")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	style := fs.String("style", "merge", "merge, rebase, rebase-merge или squash")
+	title := fs.String("title", "", "Наслов merge commit-а")
+	message := fs.String("message", "", "Порука merge commit-а")
+	deleteBranch := fs.Bool("delete-branch", false, "Обриши head грану после merge-а")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return prFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	mergeStyle := strings.ToLower(strings.TrimSpace(*style))
+	valid := false
+	for _, s := range prMergeStyles {
+		if s == mergeStyle {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("--style мора бити једно од: %s", strings.Join(prMergeStyles, ", "))
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"Do":                        mergeStyle,
+		"delete_branch_after_merge": *deleteBranch,
+	}
+	if strings.TrimSpace(*title) != "" {
+		payload["MergeTitleField"] = strings.TrimSpace(*title)
+	}
+	if strings.TrimSpace(*message) != "" {
+		payload["MergeMessageField"] = *message
+	}
+
+	endpoint := fmt.Sprintf("%s/pulls/%d/merge", repoPath(owner, repoName), number)
+	if err := client.doJSON(http.MethodPost, endpoint, payload, nil); err != nil {
+		var apiErr *giteaAPIError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusMethodNotAllowed {
+			return fmt.Errorf("PR #%d не може да се merge-ује: %s", number, apiErr.Message)
+		}
+		return issueNotFound(err, number)
+	}
+
+	fmt.Fprintln(humanOut(), colorize(fmt.Sprintf("PR #%d merge-ован (%s)", number, mergeStyle), ansiGreen, stdoutColor))
+	return nil
+}
+
+func runPRDiff(args []string) error {
+	fs := newFlagSet("pr diff")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	patch := fs.Bool("patch", false, "Patch формат уместо diff-а")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return prFlagError(err)
+	}
+	number, err := singleIssueNumber(positional)
+	if err != nil {
+		return err
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	kind := "diff"
+	if *patch {
+		kind = "patch"
+	}
+	data, err := client.raw(http.MethodGet, fmt.Sprintf("%s/pulls/%d.%s", repoPath(owner, repoName), number, kind), nil, nil, false)
+	if err != nil {
+		return issueNotFound(err, number)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func getPull(client *giteaClient, owner, repo string, number int64) (giteaPull, error) {
+	var p giteaPull
+	if err := client.doJSON(http.MethodGet, fmt.Sprintf("%s/pulls/%d", repoPath(owner, repo), number), nil, &p); err != nil {
+		return giteaPull{}, issueNotFound(err, number)
+	}
+	return p, nil
+}
+
+func prRemote() string {
+	if commandOutput("git", "remote", "get-url", defaultHostAlias) != "" {
+		return defaultHostAlias
+	}
+	return "origin"
+}
+
+func pullState(p giteaPull) string {
+	if p.Merged {
+		return "merged"
+	}
+	return p.State
+}
+
+func toPullJSON(p giteaPull) pullJSON {
+	return pullJSON{
+		Number:    p.Number,
+		Title:     p.Title,
+		State:     pullState(p),
+		Author:    p.User.Login,
+		Head:      p.Head.Ref,
+		Base:      p.Base.Ref,
+		Labels:    labelNames(p.Labels),
+		Assignees: userLogins(p.Assignees),
+		Mergeable: p.Mergeable,
+		Merged:    p.Merged,
+		URL:       p.HTMLURL,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+		MergedAt:  p.MergedAt,
+	}
+}
+
+func prFlagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		printPRUsage(os.Stdout)
+		return nil
+	}
+	printPRUsage(os.Stderr)
+	return err
+}
+
+func printPRUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s pr create [--head <грана>] [--base <грана>] [--title "..."] [--body "..." | --body-file <file|->] [--label a,b] [--assignee u] [--fill]
+  %s pr list [--state open|closed|all] [--label a,b] [--limit 30] [--json]
+  %s pr view <број> [--json]
+  %s pr checkout <број> [--branch <име>] [--remote <remote>]
+  %s pr merge <број> [--style merge|rebase|rebase-merge|squash] [--title "..."] [--message "..."] [--delete-branch]
+  %s pr diff <број> [--patch]
+
+Све подкоманде примају --repo owner/repo. Без њега repo се чита из
+gitcrn (па origin) remote-а тренутног репоа.
+
+pr create: head је тренутна грана, base је default грана репоа. Наслов и
+опис се попуњавају из commit-а (један commit: његова порука; више: листа).
+`, appName, appName, appName, appName, appName, appName)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseCommitLog(t *testing.T) {
+	out := "Add browse\x1fOpens pages.\n\nSecond para\x1e\nFix typo\x1f\x1e"
+	commits := parseCommitLog(out)
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(commits))
+	}
+	if commits[0].Subject != "Add browse" || !strings.HasPrefix(commits[0].Body, "Opens pages.") {
+		t.Fatalf("unexpected first commit: %#v", commits[0])
+	}
+	if commits[1].Subject != "Fix typo" || commits[1].Body != "" {
+		t.Fatalf("unexpected second commit: %#v", commits[1])
+	}
+}
+
+func TestPRFillFromCommits(t *testing.T) {
+	title, body := prFillFromCommits("feature/add-browse", []commitInfo{{Subject: "Add browse", Body: "details"}})
+	if title != "Add browse" || body != "details" {
+		t.Fatalf("single commit: got %q / %q", title, body)
+	}
+
+	title, body = prFillFromCommits("feature/add-browse", []commitInfo{{Subject: "One"}, {Subject: "Two"}})
+	if title != "Add browse" {
+		t.Fatalf("multi commit title: got %q", title)
+	}
+	if body != "- One\n- Two" {
+		t.Fatalf("multi commit body: got %q", body)
+	}
+
+	title, _ = prFillFromCommits("fix_login", nil)
+	if title != "Fix login" {
+		t.Fatalf("no commits title: got %q", title)
+	}
+}
+
+func TestHumanizeBranch(t *testing.T) {
+	tests := map[string]string{
+		"feature/add-browse": "Add browse",
+		"fix_login":          "Fix login",
+		"feature/поправка-пријаве": "Поправка пријаве",
+		"ђурђевдан":                "Ђурђевдан",
+		"feature/":                 "feature/",
+	}
+	for branch, want := range tests {
+		got := humanizeBranch(branch)
+		if got != want || !utf8.ValidString(got) {
+			t.Fatalf("%s: got %q, want %q", branch, got, want)
+		}
+	}
+}
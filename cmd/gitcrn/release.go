@@ -0,0 +1,618 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	checksumsAssetName = "checksums.txt"
+	transferTimeout    = 10 * time.Minute
+)
+
+type giteaAsset struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+type giteaRelease struct {
+	ID          int64        `json:"id"`
+	TagName     string       `json:"tag_name"`
+	Target      string       `json:"target_commitish"`
+	Name        string       `json:"name"`
+	Body        string       `json:"body"`
+	Draft       bool         `json:"draft"`
+	Prerelease  bool         `json:"prerelease"`
+	HTMLURL     string       `json:"html_url"`
+	CreatedAt   time.Time    `json:"created_at"`
+	PublishedAt time.Time    `json:"published_at"`
+	Assets      []giteaAsset `json:"assets"`
+}
+
+// releaseJSON is the stable --json shape of a release, independent of
+// whatever fields Gitea adds to its API.
+type releaseJSON struct {
+	Tag         string      `json:"tag"`
+	Name        string      `json:"name"`
+	Target      string      `json:"target"`
+	Draft       bool        `json:"draft"`
+	Prerelease  bool        `json:"prerelease"`
+	URL         string      `json:"url"`
+	CreatedAt   time.Time   `json:"created_at"`
+	PublishedAt time.Time   `json:"published_at"`
+	Assets      []assetJSON `json:"assets"`
+}
+
+type assetJSON struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	URL  string `json:"url"`
+}
+
+func toReleaseJSON(r giteaRelease) releaseJSON {
+	out := releaseJSON{
+		Tag:         r.TagName,
+		Name:        r.Name,
+		Target:      r.Target,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		URL:         r.HTMLURL,
+		CreatedAt:   r.CreatedAt,
+		PublishedAt: r.PublishedAt,
+		Assets:      make([]assetJSON, 0, len(r.Assets)),
+	}
+	for _, a := range r.Assets {
+		out.Assets = append(out.Assets, assetJSON{Name: a.Name, Size: a.Size, URL: a.DownloadURL})
+	}
+	return out
+}
+
+func runRelease(args []string) error {
+	if len(args) < 1 {
+		printReleaseUsage(os.Stdout)
+		return nil
+	}
+
+	switch args[0] {
+	case "create", "new":
+		return runReleaseCreate(args[1:])
+	case "list", "ls":
+		return runReleaseList(args[1:])
+	case "download":
+		return runReleaseDownload(args[1:])
+	case "delete", "rm":
+		return runReleaseDelete(args[1:])
+	case "-h", "--help", "help":
+		printReleaseUsage(os.Stdout)
+		return nil
+	default:
+		printReleaseUsage(os.Stderr)
+		return fmt.Errorf("неподржана release подкоманда: %s", args[0])
+	}
+}
+
+func runReleaseCreate(args []string) error {
+	fs := newFlagSet("release create")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	title := fs.String("title", "", "Наслов (подразумевано tag)")
+	notes := fs.String("notes", "", "Белешке")
+	notesFile := fs.String("notes-file", "", "Белешке из фајла или - за stdin")
+	target := fs.String("target", "", "Грана или commit за нови tag")
+	draft := fs.Bool("draft", false, "Draft release")
+	prerelease := fs.Bool("prerelease", false, "Prerelease")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return releaseFlagError(err)
+	}
+	if len(positional) < 1 {
+		printReleaseUsage(os.Stderr)
+		return errors.New("release create тражи tag")
+	}
+	tag := strings.TrimSpace(positional[0])
+
+	assets, err := expandAssetPatterns(positional[1:])
+	if err != nil {
+		return err
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+
+	body := *notes
+	switch {
+	case *notes != "" && *notesFile != "":
+		return errors.New("користи или --notes или --notes-file, не оба")
+	case *notesFile != "":
+		body, err = resolveBody("", *notesFile, "")
+		if err != nil {
+			return err
+		}
+	case *notes == "":
+		body = generateReleaseNotes(tag)
+	}
+
+	payload := map[string]any{
+		"tag_name":   tag,
+		"name":       fallback(strings.TrimSpace(*title), tag),
+		"body":       body,
+		"draft":      *draft,
+		"prerelease": *prerelease,
+	}
+	if strings.TrimSpace(*target) != "" {
+		payload["target_commitish"] = strings.TrimSpace(*target)
+	}
+
+	var rel giteaRelease
+	if err := client.doJSON(http.MethodPost, repoPath(owner, repoName)+"/releases", payload, &rel); err != nil {
+		var apiErr *giteaAPIError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusConflict {
+			return fmt.Errorf("release %s већ постоји", tag)
+		}
+		return fmt.Errorf("креирање release-а није успело: %w", err)
+	}
+	fmt.Fprintln(humanOut(), colorize("Release креиран: "+tag, ansiGreen, stdoutColor))
+
+	for _, path := range assets {
+		fmt.Fprintf(humanOut(), "Upload: %s\n", path)
+		if err := client.uploadReleaseAsset(owner, repoName, rel.ID, path); err != nil {
+			return fmt.Errorf("upload %s: %w", path, err)
+		}
+	}
+	if rel.HTMLURL != "" {
+		fmt.Fprintln(humanOut(), rel.HTMLURL)
+	}
+	return nil
+}
+
+func expandAssetPatterns(patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("невалидан glob %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("ниједан фајл не одговара: %s", pattern)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			if !fileExists(m) || seen[m] {
+				continue
+			}
+			seen[m] = true
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// generateReleaseNotes lists commits since the previous tag. When tag does
+// not exist locally yet, HEAD is treated as the release point.
+func generateReleaseNotes(tag string) string {
+	head, prevFrom := "HEAD", "HEAD"
+	if commandOutput("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+tag) != "" {
+		head, prevFrom = tag, tag+"^"
+	}
+	prev := commandOutput("git", "describe", "--tags", "--abbrev=0", prevFrom)
+
+	rangeSpec := head
+	if prev != "" {
+		rangeSpec = prev + ".." + head
+	}
+	log := commandOutput("git", "log", "--no-merges", "--format=- %s (%h)", rangeSpec)
+	if log == "" {
+		return ""
+	}
+	if prev != "" {
+		return fmt.Sprintf("## Измене од %s\n\n%s\n", prev, log)
+	}
+	return "## Измене\n\n" + log + "\n"
+}
+
+func (c *giteaClient) uploadReleaseAsset(owner, repo string, releaseID int64, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("attachment", filepath.Base(path))
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/releases/%d/assets?name=%s", repoPath(owner, repo), releaseID, url.QueryEscape(filepath.Base(path)))
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint, pr)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &giteaAPIError{Status: resp.StatusCode, Message: giteaErrorMessage(resp.Body)}
+	}
+	return nil
+}
+
+func runReleaseList(args []string) error {
+	fs := newFlagSet("release list")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	limit := fs.Int("limit", 30, "Максималан број резултата")
+
+	if err := fs.Parse(args); err != nil {
+		return releaseFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		printReleaseUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+	if *limit <= 0 {
+		return errors.New("--limit мора бити већи од 0")
+	}
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+	releases, err := listPages[giteaRelease](client, repoPath(owner, repoName)+"/releases", url.Values{}, *limit)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		out := make([]releaseJSON, 0, len(releases))
+		for _, r := range releases {
+			out = append(out, toReleaseJSON(r))
+		}
+		return writeJSON(os.Stdout, out)
+	}
+	if len(releases) == 0 {
+		fmt.Fprintln(os.Stderr, "Нема release-ова.")
+		return nil
+	}
+
+	t := newTableWriter(os.Stdout, "TAG", "НАСЛОВ", "ВРСТА", "ФАЈЛОВИ", "ОБЈАВЉЕНО")
+	for _, r := range releases {
+		kind := "stable"
+		switch {
+		case r.Draft:
+			kind = "draft"
+		case r.Prerelease:
+			kind = "prerelease"
+		}
+		published := ""
+		if !r.PublishedAt.IsZero() {
+			published = r.PublishedAt.Local().Format("2006-01-02")
+		}
+		t.Row(r.TagName, t.Cell(r.Name, 40), kind, strconv.Itoa(len(r.Assets)), published)
+	}
+	t.Flush()
+	return nil
+}
+
+func runReleaseDownload(args []string) error {
+	fs := newFlagSet("release download")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	pattern := fs.String("pattern", "", "Glob за имена фајлова (нпр gitcrn-linux-*)")
+	dir := fs.String("dir", ".", "Директоријум за преузимање")
+	skipVerify := fs.Bool("skip-verify", false, "Не проверавај SHA-256")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return releaseFlagError(err)
+	}
+	if len(positional) != 1 {
+		printReleaseUsage(os.Stderr)
+		return errors.New("release download тражи tag")
+	}
+	tag := positional[0]
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+	rel, err := getReleaseByTag(client, owner, repoName, tag)
+	if err != nil {
+		return err
+	}
+
+	var selected []giteaAsset
+	var checksumsAsset *giteaAsset
+	for i, a := range rel.Assets {
+		if a.Name == checksumsAssetName {
+			checksumsAsset = &rel.Assets[i]
+		}
+		if *pattern != "" {
+			if ok, err := filepath.Match(*pattern, a.Name); err != nil {
+				return fmt.Errorf("невалидан --pattern: %w", err)
+			} else if !ok {
+				continue
+			}
+		}
+		selected = append(selected, a)
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("release %s нема фајлове који одговарају", tag)
+	}
+
+	var sums map[string]string
+	if !*skipVerify && checksumsAsset != nil {
+		data, err := client.fetchBytes(checksumsAsset.DownloadURL)
+		if err != nil {
+			return fmt.Errorf("преузимање %s: %w", checksumsAssetName, err)
+		}
+		sums = parseChecksums(string(data))
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+	for _, a := range selected {
+		dest := filepath.Join(*dir, a.Name)
+		fmt.Fprintf(humanOut(), "Преузимам %s\n", a.Name)
+		// The checksum is checked on the temp file, so a bad download never
+		// replaces a file already at dest.
+		var verify func(string) error
+		if a.Name != checksumsAssetName && !*skipVerify && sums != nil {
+			name := a.Name
+			verify = func(path string) error { return verifyChecksum(path, name, sums) }
+		}
+		if err := client.downloadFile(a.DownloadURL, dest, verify); err != nil {
+			return fmt.Errorf("преузимање %s: %w", a.Name, err)
+		}
+		if a.Name == checksumsAssetName || *skipVerify {
+			continue
+		}
+		if sums == nil {
+			fmt.Fprintln(os.Stderr, colorize("Упозорење: release нема "+checksumsAssetName+", SHA-256 није проверен", ansiYellow, stderrColor))
+			continue
+		}
+		fmt.Fprintln(humanOut(), colorize("  SHA-256 OK", ansiGreen, stdoutColor))
+	}
+	return nil
+}
+
+func runReleaseDelete(args []string) error {
+	fs := newFlagSet("release delete")
+	repoFlag := fs.String("repo", "", "owner/repo")
+	yes := fs.Bool("yes", false, "Без потврде")
+	cleanupTag := fs.Bool("cleanup-tag", false, "Обриши и git tag на серверу")
+
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return releaseFlagError(err)
+	}
+	if len(positional) != 1 {
+		printReleaseUsage(os.Stderr)
+		return errors.New("release delete тражи tag")
+	}
+	tag := positional[0]
+
+	client, owner, repoName, err := repoClient(*repoFlag)
+	if err != nil {
+		return err
+	}
+	rel, err := getReleaseByTag(client, owner, repoName, tag)
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		ok, err := promptYesNo(os.Stderr, stdin, fmt.Sprintf("Обрисати release %s (%s/%s)? [y/N]: ", tag, owner, repoName))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("прекинуто")
+		}
+	}
+
+	if err := client.doJSON(http.MethodDelete, fmt.Sprintf("%s/releases/%d", repoPath(owner, repoName), rel.ID), nil, nil); err != nil {
+		return fmt.Errorf("брисање release-а: %w", err)
+	}
+	fmt.Fprintln(humanOut(), colorize("Release обрисан: "+tag, ansiGreen, stdoutColor))
+
+	if *cleanupTag {
+		if err := client.doJSON(http.MethodDelete, repoPath(owner, repoName)+"/tags/"+url.PathEscape(tag), nil, nil); err != nil {
+			return fmt.Errorf("брисање tag-а: %w", err)
+		}
+		fmt.Fprintln(humanOut(), colorize("Tag обрисан: "+tag, ansiGreen, stdoutColor))
+	}
+	return nil
+}
+
+func getReleaseByTag(client *giteaClient, owner, repo, tag string) (giteaRelease, error) {
+	var rel giteaRelease
+	err := client.doJSON(http.MethodGet, repoPath(owner, repo)+"/releases/tags/"+url.PathEscape(tag), nil, &rel)
+	if isNotFound(err) {
+		return giteaRelease{}, fmt.Errorf("release %s не постоји", tag)
+	}
+	return rel, err
+}
+
+// authorizedGet only sends the token to the configured server so release
+// assets hosted elsewhere never see it.
+func (c *giteaClient) authorizedGet(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", appName)
+	if c.token != "" && c.isServerURL(req.URL) {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, &giteaAPIError{Status: resp.StatusCode, Message: giteaErrorMessage(resp.Body)}
+	}
+	return resp, nil
+}
+
+func (c *giteaClient) fetchBytes(rawURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+	resp, err := c.authorizedGet(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// downloadFile saves rawURL to dest. verify, when set, runs on the complete
+// temp file and dest is only replaced if it passes.
+func (c *giteaClient) downloadFile(rawURL, dest string, verify func(string) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
+	defer cancel()
+	resp, err := c.authorizedGet(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return writeFileAtomic(dest, resp.Body, 0o644, verify)
+}
+
+func writeFileAtomic(dest string, r io.Reader, mode os.FileMode, verify func(string) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if verify != nil {
+		if err := verify(tmpName); err != nil {
+			os.Remove(tmpName)
+			return err
+		}
+	}
+	if err := os.Rename(tmpName, dest); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// parseChecksums reads sha256sum/shasum output ("<hex>  <name>", with an
+// optional "*" binary marker) as produced by scripts/release.sh.
+func parseChecksums(content string) map[string]string {
+	sums := map[string]string{}
+	sc := bufio.NewScanner(strings.NewReader(normalizeNewlines(content)))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 || len(fields[0]) != sha256.Size*2 {
+			continue
+		}
+		name := strings.TrimPrefix(strings.Join(fields[1:], " "), "*")
+		sums[filepath.Base(name)] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func verifyChecksum(path, name string, sums map[string]string) error {
+	want, ok := sums[name]
+	if !ok {
+		return fmt.Errorf("%s нема запис у %s", name, checksumsAssetName)
+	}
+	got, err := sha256File(path)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("SHA-256 се не поклапа за %s: очекивано %s, добијено %s", name, want, got)
+	}
+	return nil
+}
+
+func releaseFlagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		printReleaseUsage(os.Stdout)
+		return nil
+	}
+	printReleaseUsage(os.Stderr)
+	return err
+}
+
+func printReleaseUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s release create <tag> [фајлови или glob...] [--title "..."] [--notes "..." | --notes-file <file|->] [--target <грана>] [--draft] [--prerelease]
+  %s release list [--limit 30] [--json]
+  %s release download <tag> [--pattern <glob>] [--dir <dir>] [--skip-verify]
+  %s release delete <tag> [--yes] [--cleanup-tag]
+
+Све подкоманде примају --repo owner/repo. Без њега repo се чита из
+gitcrn (па origin) remote-а тренутног репоа.
+
+Без --notes белешке се праве из commit-а од претходног tag-а.
+download проверава SHA-256 према checksums.txt из истог release-а.
+
+Пример:
+  ./scripts/release.sh --version v0.6.0
+  %s release create v0.6.0 'dist/*'
+`, appName, appName, appName, appName, appName)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	a := strings.Repeat("a", 64)
+	b := strings.Repeat("B", 64)
+	content := a + "  gitcrn-linux-amd64\r\n" + b + " *gitcrn-windows-amd64.exe\nnot a checksum line\n"
+
+	sums := parseChecksums(content)
+	if sums["gitcrn-linux-amd64"] != a {
+		t.Fatalf("unexpected linux sum: %q", sums["gitcrn-linux-amd64"])
+	}
+	if sums["gitcrn-windows-amd64.exe"] != strings.ToLower(b) {
+		t.Fatalf("binary marker should be stripped and hex lowercased: %#v", sums)
+	}
+	if len(sums) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(sums))
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gitcrn-linux-amd64")
+	if err := os.WriteFile(path, []byte("binary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("binary"))
+	good := map[string]string{"gitcrn-linux-amd64": hex.EncodeToString(sum[:])}
+
+	if err := verifyChecksum(path, "gitcrn-linux-amd64", good); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bad := map[string]string{"gitcrn-linux-amd64": strings.Repeat("0", 64)}
+	if err := verifyChecksum(path, "gitcrn-linux-amd64", bad); err == nil {
+		t.Fatalf("expected mismatch error")
+	}
+	if err := verifyChecksum(path, "gitcrn-linux-amd64", map[string]string{}); err == nil {
+		t.Fatalf("expected missing entry error")
+	}
+}
+
+func TestDownloadFileKeepsGoodFileOnMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "corrupt")
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "gitcrn-linux-amd64")
+	if err := os.WriteFile(dest, []byte("binary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("binary"))
+	sums := map[string]string{"gitcrn-linux-amd64": hex.EncodeToString(sum[:])}
+
+	client := &giteaClient{serverURL: srv.URL}
+	err := client.downloadFile(srv.URL+"/asset", dest, func(path string) error {
+		return verifyChecksum(path, "gitcrn-linux-amd64", sums)
+	})
+	if err == nil {
+		t.Fatal("expected mismatch error")
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "binary" {
+		t.Fatalf("existing file was replaced: %q", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(dest)); len(entries) != 1 {
+		t.Fatalf("temp file left behind: %v", entries)
+	}
+}
+
+func TestExpandAssetPatterns(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"gitcrn-linux-amd64", "gitcrn-linux-arm64", "checksums.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := expandAssetPatterns([]string{filepath.Join(dir, "*"), filepath.Join(dir, "checksums.txt")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected deduplicated 3 assets, got %v", got)
+	}
+
+	if _, err := expandAssetPatterns([]string{filepath.Join(dir, "missing-*")}); err == nil {
+		t.Fatalf("expected error for pattern without matches")
+	}
+}
+
+func TestUploadReleaseAsset(t *testing.T) {
+	var gotName, gotContent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/vltc/kapri/releases/5/assets" {
+			http.NotFound(w, r)
+			return
+		}
+		gotName = r.URL.Query().Get("name")
+		f, _, err := r.FormFile("attachment")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"message":%q}`, err.Error())
+			return
+		}
+		data, _ := io.ReadAll(f)
+		gotContent = string(data)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":1}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "checksums.txt")
+	if err := os.WriteFile(path, []byte("sums"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &giteaClient{serverURL: srv.URL, token: "t"}
+	if err := client.uploadReleaseAsset("vltc", "kapri", 5, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotName != "checksums.txt" || gotContent != "sums" {
+		t.Fatalf("unexpected upload: name=%q content=%q", gotName, gotContent)
+	}
+}
+
+func TestToReleaseJSON(t *testing.T) {
+	var rel giteaRelease
+	raw := `{"id":5,"tag_name":"v1.0.0","name":"Први","prerelease":true,"html_url":"https://g/r/v1.0.0","author":{"login":"x"},"assets":[{"id":1,"name":"checksums.txt","size":64,"browser_download_url":"https://g/a"}]}`
+	if err := json.Unmarshal([]byte(raw), &rel); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(toReleaseJSON(rel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"assets", "created_at", "draft", "name", "prerelease", "published_at", "tag", "target", "url"}
+	var got []string
+	for k := range fields {
+		got = append(got, k)
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected --json fields: %v", got)
+	}
+	if !strings.Contains(string(data), `"assets":[{"name":"checksums.txt","size":64,"url":"https://g/a"}]`) {
+		t.Fatalf("unexpected assets: %s", data)
+	}
+}
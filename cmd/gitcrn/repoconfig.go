@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// repoConfigName is the per-repo file with push/pull settings. It lives in
+// the repo root and can be committed, so every clone pushes the same way.
+const repoConfigName = ".gitcrn.toml"
+
+// defaultPushTimeout bounds each remote's push when push_timeout is not set.
+const defaultPushTimeout = 60 * time.Second
+
+type repoConfig struct {
+	Branch      string
+	Message     string
+	PushRemotes []string
+	PullRemotes []string
+	// RequiredRemotes are the push remotes whose failure fails the push.
+	// Empty means all of them.
+	RequiredRemotes []string
+	PushTimeout     time.Duration
+}
+
+// required returns the push remotes that must succeed.
+func (rc repoConfig) required() []string {
+	if len(rc.RequiredRemotes) == 0 {
+		return rc.PushRemotes
+	}
+	return rc.RequiredRemotes
+}
+
+func repoConfigTemplate() string {
+	return strings.Join([]string{
+		"# " + appName + " push / pull за овај репо (" + appName + " make / remake)",
+		"# push: git add ., commit са message, па push на push_remotes",
+		"# pull: git pull са pull_remotes",
+	}, "\n") + "\n"
+}
+
+// gitRepoRoot returns the top-level directory of the current git repo.
+func gitRepoRoot() (string, error) {
+	root := strings.TrimSpace(commandOutput("git", "rev-parse", "--show-toplevel"))
+	if root == "" || !filepath.IsAbs(filepath.FromSlash(root)) {
+		return "", errors.New("ова команда мора да се покрене унутар git репозиторијума")
+	}
+	return filepath.FromSlash(root), nil
+}
+
+// loadRepoConfig reads .gitcrn.toml from root. found is false when the file
+// does not exist.
+func loadRepoConfig(root string) (rc repoConfig, found bool, err error) {
+	path := filepath.Join(root, repoConfigName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rc, false, nil
+	}
+	if err != nil {
+		return rc, false, fmt.Errorf("читање %s: %w", path, err)
+	}
+	rc, err = parseRepoConfig(string(data))
+	if err != nil {
+		return rc, true, fmt.Errorf("%s: %w", path, err)
+	}
+	return rc, true, nil
+}
+
+// parseRepoConfig reads the top-level keys of .gitcrn.toml. Unlike
+// config.toml, values here are free text (commit messages), so strings are
+// parsed properly and a "#" inside quotes is not a comment. Remote lists can
+// be TOML arrays or a single "a, b" string.
+func parseRepoConfig(content string) (repoConfig, error) {
+	var rc repoConfig
+	for i, raw := range strings.Split(normalizeNewlines(content), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			break
+		}
+
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return rc, fmt.Errorf("ред %d: очекује се key = value", i+1)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		values, err := parseTOMLValue(strings.TrimSpace(val))
+		if err != nil {
+			return rc, fmt.Errorf("ред %d (%s): %w", i+1, key, err)
+		}
+
+		switch key {
+		case "branch":
+			rc.Branch = strings.Join(values, " ")
+		case "message":
+			rc.Message = strings.Join(values, " ")
+		case "push_remotes":
+			rc.PushRemotes = parseRemoteList(strings.Join(values, ","))
+		case "pull_remotes":
+			rc.PullRemotes = parseRemoteList(strings.Join(values, ","))
+		case "required_remotes":
+			rc.RequiredRemotes = parseRemoteList(strings.Join(values, ","))
+		case "push_timeout":
+			d, err := time.ParseDuration(strings.Join(values, ""))
+			if err != nil || d <= 0 {
+				return rc, fmt.Errorf("ред %d (push_timeout): очекује се трајање, нпр. \"30s\"", i+1)
+			}
+			rc.PushTimeout = d
+		}
+	}
+	return rc, nil
+}
+
+// parseTOMLValue parses a string, a bare word or a one-line array of
+// strings, with an optional trailing comment. A single value is returned as
+// a one-element slice.
+func parseTOMLValue(s string) ([]string, error) {
+	if !strings.HasPrefix(s, "[") {
+		v, rest, err := parseTOMLString(s)
+		if err != nil {
+			return nil, err
+		}
+		if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+			return nil, fmt.Errorf("вишак после вредности: %s", rest)
+		}
+		return []string{v}, nil
+	}
+
+	var values []string
+	rest := strings.TrimSpace(s[1:])
+	for {
+		if strings.HasPrefix(rest, "]") {
+			rest = strings.TrimSpace(rest[1:])
+			break
+		}
+		if rest == "" {
+			return nil, errors.New("низ није затворен са ]")
+		}
+		v, r, err := parseTOMLString(rest)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		rest = strings.TrimSpace(r)
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, ","))
+	}
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return nil, fmt.Errorf("вишак после низа: %s", rest)
+	}
+	return values, nil
+}
+
+// parseTOMLString reads one value at the start of s and returns the rest.
+func parseTOMLString(s string) (value, rest string, err error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				v, err := strconv.Unquote(s[:i+1])
+				if err != nil {
+					return "", "", fmt.Errorf("неисправан string %s", s[:i+1])
+				}
+				return v, s[i+1:], nil
+			}
+		}
+		return "", "", errors.New("string није затворен")
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", "", errors.New("string није затворен")
+		}
+		return s[1 : end+1], s[end+2:], nil
+	default:
+		end := strings.IndexAny(s, ",]#")
+		if end < 0 {
+			end = len(s)
+		}
+		return strings.TrimSpace(s[:end]), s[end:], nil
+	}
+}
+
+// saveRepoConfig writes values into .gitcrn.toml, keeping comments and keys
+// it does not set.
+func saveRepoConfig(root string, values []configEntry) (string, error) {
+	path := filepath.Join(root, repoConfigName)
+	var content string
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		content = setAppConfigValues(normalizeNewlines(string(data)), values)
+	case errors.Is(err, os.ErrNotExist):
+		var sb strings.Builder
+		sb.WriteString(repoConfigTemplate() + "\n")
+		for _, v := range values {
+			sb.WriteString(v.Key + " = " + formatConfigValue(v.Value) + "\n")
+		}
+		content = sb.String()
+	default:
+		return "", fmt.Errorf("читање %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("упис %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRepoConfig(t *testing.T) {
+	rc, err := parseRepoConfig(strings.Join([]string{
+		"# коментар",
+		`branch = "main" # грана`,
+		`message = "fix #12: \"quoted\""`,
+		`push_remotes = ["gitcrn", 'origin',]`,
+		`pull_remotes = "gitcrn, backup"`,
+		`required_remotes = ["gitcrn"]`,
+		`push_timeout = "30s"`,
+		`unknown = 1`,
+		"",
+		"[later]",
+		`branch = "ignored"`,
+	}, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Branch != "main" || rc.Message != `fix #12: "quoted"` {
+		t.Fatalf("unexpected strings: %+v", rc)
+	}
+	if strings.Join(rc.PushRemotes, ",") != "gitcrn,origin" || strings.Join(rc.PullRemotes, ",") != "gitcrn,backup" {
+		t.Fatalf("unexpected remotes: %+v", rc)
+	}
+	if strings.Join(rc.required(), ",") != "gitcrn" || rc.PushTimeout != 30*time.Second {
+		t.Fatalf("unexpected push settings: %+v", rc)
+	}
+	if rc.RequiredRemotes = nil; strings.Join(rc.required(), ",") != "gitcrn,origin" {
+		t.Fatalf("all push remotes should be required by default: %v", rc.required())
+	}
+
+	for _, bad := range []string{`branch = "main`, `push_remotes = ["a"`, `branch = "a" b`, `branch`, `push_timeout = "soon"`} {
+		if _, err := parseRepoConfig(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestSaveRepoConfigRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	if _, err := saveRepoConfig(root, []configEntry{
+		{"branch", "main"},
+		{"message", `sync "#1"`},
+		{"push_remotes", []string{"gitcrn", "origin"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, repoConfigName)
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, append(data, []byte("# моје\n")...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saveRepoConfig(root, []configEntry{{"branch", "dev"}, {"pull_remotes", []string{"gitcrn"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, found, err := loadRepoConfig(root)
+	if err != nil || !found {
+		t.Fatalf("load: %v, %v", found, err)
+	}
+	if rc.Branch != "dev" || rc.Message != `sync "#1"` || strings.Join(rc.PushRemotes, ",") != "gitcrn,origin" || strings.Join(rc.PullRemotes, ",") != "gitcrn" {
+		t.Fatalf("unexpected config: %+v", rc)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "# моје") {
+		t.Fatalf("comments must be kept:\n%s", data)
+	}
+
+	if _, found, err := loadRepoConfig(t.TempDir()); found || err != nil {
+		t.Fatalf("missing file: %v, %v", found, err)
+	}
+}
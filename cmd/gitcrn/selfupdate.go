@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type selfUpdateOptions struct {
+	Source  updateSource
+	Tag     string
+	GOOS    string
+	GOARCH  string
+	ExePath string
+}
+
+func runSelfUpdate(args []string) error {
+	fs := newFlagSet("self-update")
+	wantVersion := fs.String("version", "", "Верзија (нпр v0.6.0), подразумевано најновија")
+	rollback := fs.Bool("rollback", false, "Врати претходну верзију из backup-а")
+	force := fs.Bool("force", false, "Инсталирај и ако је верзија иста")
+	channel := fs.String("channel", "", "stable или prerelease (подразумевано update_channel из config-а)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printSelfUpdateUsage(os.Stdout)
+			return nil
+		}
+		printSelfUpdateUsage(os.Stderr)
+		return err
+	}
+	if fs.NArg() != 0 {
+		printSelfUpdateUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+
+	exePath, err := currentExecutable()
+	if err != nil {
+		return err
+	}
+
+	if *rollback {
+		if *wantVersion != "" {
+			return errors.New("--rollback се не може комбиновати са --version")
+		}
+		if err := rollbackExecutable(exePath); err != nil {
+			return err
+		}
+		fmt.Println(colorize("Враћена претходна верзија: "+exePath, ansiGreen, stdoutColor))
+		return nil
+	}
+
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return err
+	}
+
+	switch c := strings.ToLower(strings.TrimSpace(*channel)); c {
+	case "":
+	case updateChannelStable, updateChannelPrerelease:
+		cfg.UpdateChannel = c
+	default:
+		return fmt.Errorf("непознат канал %q (stable или prerelease)", *channel)
+	}
+
+	src := resolveUpdateSource(cfg)
+	explicit := strings.TrimSpace(*wantVersion) != ""
+	tag, err := selfUpdateTag(*wantVersion, func() (string, error) {
+		return fetchLatestReleaseTag(src, resolveUpdateChannel(cfg), 8*time.Second)
+	})
+	if err != nil {
+		return err
+	}
+
+	if cmp, ok := compareSemver(tag, version); ok && !*force {
+		if cmp == 0 {
+			fmt.Println(colorize("Већ имаш верзију "+version, ansiGreen, stdoutColor))
+			return nil
+		}
+		if cmp < 0 && !explicit {
+			fmt.Println(colorize(fmt.Sprintf("Тренутна верзија %s је новија од %s", version, tag), ansiGreen, stdoutColor))
+			return nil
+		}
+	}
+
+	opts := selfUpdateOptions{
+		Source:  src,
+		Tag:     tag,
+		GOOS:    runtime.GOOS,
+		GOARCH:  runtime.GOARCH,
+		ExePath: exePath,
+	}
+	fmt.Printf("Преузимам %s (%s)\n", releaseAssetName(opts.GOOS, opts.GOARCH), tag)
+	if err := selfUpdate(opts); err != nil {
+		return err
+	}
+
+	fmt.Println(colorize(fmt.Sprintf("Ажурирано: %s -> %s", version, tag), ansiGreen, stdoutColor))
+	fmt.Printf("Претходна верзија је сачувана као %s (врати са: %s self-update --rollback)\n", backupPath(exePath), appName)
+	return nil
+}
+
+// selfUpdateTag returns the tag to install. A --version typed without "v"
+// gets one, as the release tags have it; a tag read from the release source
+// is used exactly as returned, since download URLs are built from it.
+func selfUpdateTag(want string, fetchLatest func() (string, error)) (string, error) {
+	if tag := strings.TrimSpace(want); tag != "" {
+		if !strings.HasPrefix(tag, "v") {
+			tag = "v" + tag
+		}
+		return tag, nil
+	}
+	tag, err := fetchLatest()
+	if err != nil {
+		return "", fmt.Errorf("не могу да прочитам најновију верзију: %w", err)
+	}
+	return tag, nil
+}
+
+func releaseAssetName(goos, goarch string) string {
+	name := fmt.Sprintf("%s-%s-%s", appName, goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// selfUpdate downloads the release asset and checksums.txt, verifies the
+// SHA-256 and swaps the executable, keeping the old one as a backup.
+func selfUpdate(opts selfUpdateOptions) error {
+	asset := releaseAssetName(opts.GOOS, opts.GOARCH)
+	base := strings.TrimRight(opts.Source.DownloadURL, "/") + "/" + url.PathEscape(opts.Tag) + "/"
+
+	sumsData, err := opts.Source.get(base+checksumsAssetName, 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("преузимање %s: %w", checksumsAssetName, err)
+	}
+	sums := parseChecksums(string(sumsData))
+	if _, ok := sums[asset]; !ok {
+		return fmt.Errorf("%s нема запис за %s", checksumsAssetName, asset)
+	}
+
+	dir := filepath.Dir(opts.ExePath)
+	newPath := filepath.Join(dir, "."+filepath.Base(opts.ExePath)+".new")
+	binData, err := opts.Source.get(base+asset, transferTimeout)
+	if err != nil {
+		return fmt.Errorf("преузимање %s: %w", asset, err)
+	}
+	if err := writeFileAtomic(newPath, bytes.NewReader(binData), 0o755, nil); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("нема дозволе за упис у %s. Покрени са одговарајућим правима или користи install скрипту", dir)
+		}
+		return err
+	}
+
+	if err := verifyChecksum(newPath, asset, sums); err != nil {
+		os.Remove(newPath)
+		return err
+	}
+
+	return replaceExecutable(opts.ExePath, newPath)
+}
+
+func replaceExecutable(exePath, newPath string) error {
+	backup := backupPath(exePath)
+	os.Remove(backup)
+	if err := os.Rename(exePath, backup); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("backup %s: %w", exePath, err)
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		if restoreErr := os.Rename(backup, exePath); restoreErr != nil {
+			return fmt.Errorf("замена није успела (%v), а ни враћање backup-а (%v). Backup: %s", err, restoreErr, backup)
+		}
+		return fmt.Errorf("замена %s: %w", exePath, err)
+	}
+	return nil
+}
+
+// rollbackExecutable swaps the executable with its backup, so running it
+// twice returns to the newer version.
+func rollbackExecutable(exePath string) error {
+	backup := backupPath(exePath)
+	if !fileExists(backup) {
+		return fmt.Errorf("нема backup-а за враћање (%s)", backup)
+	}
+
+	tmp := exePath + ".rollback"
+	os.Remove(tmp)
+	if err := os.Rename(exePath, tmp); err != nil {
+		return fmt.Errorf("rollback: %w", err)
+	}
+	if err := os.Rename(backup, exePath); err != nil {
+		os.Rename(tmp, exePath)
+		return fmt.Errorf("rollback: %w", err)
+	}
+	if err := os.Rename(tmp, backup); err != nil {
+		return fmt.Errorf("rollback: тренутна верзија није сачувана као backup: %w", err)
+	}
+	return nil
+}
+
+func backupPath(exePath string) string {
+	return exePath + ".old"
+}
+
+func currentExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("детекција путање програма: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
+
+func printSelfUpdateUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s self-update [--version vX.Y.Z] [--channel stable|prerelease] [--force]
+  %s self-update --rollback
+
+Преузима %s-<os>-<arch> из release-а, проверава SHA-256 према checksums.txt
+и мења тренутни програм. Претходна верзија остаје као <програм>.old.
+
+Извор: update_provider (github или gitea на server_url), update_owner и
+update_repo у config.toml. update_base_url или GITCRN_UPDATE_BASE_URL мења
+само адресу за преузимање.
+`, appName, appName, appName)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newReleaseStandIn(t *testing.T, tag string, assets map[string][]byte, sums string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := "/" + tag + "/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+		if name == checksumsAssetName {
+			fmt.Fprint(w, sums)
+			return
+		}
+		data, ok := assets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+}
+
+func checksumLine(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "  " + name + "\n"
+}
+
+func TestSelfUpdateReplacesAndRollsBack(t *testing.T) {
+	asset := releaseAssetName("linux", "amd64")
+	newBin := []byte("new-binary")
+	srv := newReleaseStandIn(t, "v9.9.9", map[string][]byte{asset: newBin}, checksumLine(asset, newBin))
+	defer srv.Close()
+
+	exe := filepath.Join(t.TempDir(), "gitcrn")
+	if err := os.WriteFile(exe, []byte("old-binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := selfUpdateOptions{Source: updateSource{DownloadURL: srv.URL}, Tag: "v9.9.9", GOOS: "linux", GOARCH: "amd64", ExePath: exe}
+	if err := selfUpdate(opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "new-binary" {
+		t.Fatalf("executable not replaced: %q", got)
+	}
+	if got, _ := os.ReadFile(backupPath(exe)); string(got) != "old-binary" {
+		t.Fatalf("backup missing: %q", got)
+	}
+
+	if err := rollbackExecutable(exe); err != nil {
+		t.Fatalf("rollback failed: %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old-binary" {
+		t.Fatalf("rollback did not restore old binary: %q", got)
+	}
+	if got, _ := os.ReadFile(backupPath(exe)); string(got) != "new-binary" {
+		t.Fatalf("rollback should keep newer binary as backup: %q", got)
+	}
+}
+
+func TestSelfUpdateRejectsChecksumMismatch(t *testing.T) {
+	asset := releaseAssetName("linux", "arm64")
+	srv := newReleaseStandIn(t, "v1.0.0", map[string][]byte{asset: []byte("tampered")}, checksumLine(asset, []byte("original")))
+	defer srv.Close()
+
+	exe := filepath.Join(t.TempDir(), "gitcrn")
+	if err := os.WriteFile(exe, []byte("old-binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := selfUpdate(selfUpdateOptions{Source: updateSource{DownloadURL: srv.URL}, Tag: "v1.0.0", GOOS: "linux", GOARCH: "arm64", ExePath: exe})
+	if err == nil || !strings.Contains(err.Error(), "SHA-256") {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old-binary" {
+		t.Fatalf("executable must stay untouched on mismatch: %q", got)
+	}
+	if fileExists(backupPath(exe)) {
+		t.Fatalf("no backup should be created on mismatch")
+	}
+}
+
+func TestReleaseAssetName(t *testing.T) {
+	if got := releaseAssetName("windows", "arm64"); got != "gitcrn-windows-arm64.exe" {
+		t.Fatalf("unexpected windows asset: %s", got)
+	}
+	if got := releaseAssetName("linux", "amd64"); got != "gitcrn-linux-amd64" {
+		t.Fatalf("unexpected linux asset: %s", got)
+	}
+}
+
+func TestSelfUpdateTag(t *testing.T) {
+	latest := func() (string, error) { return "0.7.0", nil }
+	if tag, err := selfUpdateTag("", latest); err != nil || tag != "0.7.0" {
+		t.Fatalf("fetched tag must be used as is: %q, %v", tag, err)
+	}
+	if tag, _ := selfUpdateTag("0.6.0", latest); tag != "v0.6.0" {
+		t.Fatalf("typed version should get a v prefix: %q", tag)
+	}
+	if tag, _ := selfUpdateTag("v0.6.0", latest); tag != "v0.6.0" {
+		t.Fatalf("unexpected tag %q", tag)
+	}
+}
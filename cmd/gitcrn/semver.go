@@ -0,0 +1,168 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed SemVer 2.0 version. Build metadata is kept only for
+// display; it never affects precedence.
+type semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      string
+}
+
+// parseSemver accepts an optional "v" prefix and, for older tags, a core
+// with fewer than three components (missing ones are zero).
+func parseSemver(v string) (semver, bool) {
+	s := strings.TrimSpace(v)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if s == "" {
+		return semver{}, false
+	}
+
+	var out semver
+	if i := strings.Index(s, "+"); i >= 0 {
+		out.Build = s[i+1:]
+		if !validSemverIdentifiers(out.Build, false) {
+			return semver{}, false
+		}
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		pre := s[i+1:]
+		if !validSemverIdentifiers(pre, true) {
+			return semver{}, false
+		}
+		out.Prerelease = strings.Split(pre, ".")
+		s = s[:i]
+	}
+
+	core := strings.Split(s, ".")
+	if len(core) > 3 {
+		return semver{}, false
+	}
+	nums := make([]int, 3)
+	for i, seg := range core {
+		// SemVer forbids leading zeros in numeric identifiers ("01.2.3").
+		if !isNumericIdentifier(seg) || len(seg) > 1 && seg[0] == '0' {
+			return semver{}, false
+		}
+		n, err := strconv.Atoi(seg)
+		if err != nil {
+			return semver{}, false
+		}
+		nums[i] = n
+	}
+	out.Major, out.Minor, out.Patch = nums[0], nums[1], nums[2]
+	return out, true
+}
+
+func validSemverIdentifiers(s string, rejectLeadingZero bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+		if rejectLeadingZero && isNumericIdentifier(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumericIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (v semver) isPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// compareSemver compares a and b by SemVer 2.0 precedence. ok is false when
+// either side is not a version (for example a "dev" build).
+func compareSemver(a, b string) (int, bool) {
+	av, okA := parseSemver(a)
+	bv, okB := parseSemver(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	return av.compare(bv), true
+}
+
+func (v semver) compare(o semver) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	// A version without prerelease has higher precedence.
+	switch {
+	case !v.isPrerelease() && !o.isPrerelease():
+		return 0
+	case !v.isPrerelease():
+		return 1
+	case !o.isPrerelease():
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := comparePrereleaseIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.Prerelease), len(o.Prerelease))
+}
+
+// comparePrereleaseIdentifier orders numeric identifiers numerically and
+// below alphanumeric ones, which compare in ASCII order.
+func comparePrereleaseIdentifier(a, b string) int {
+	aNum, bNum := isNumericIdentifier(a), isNumericIdentifier(b)
+	switch {
+	case aNum && bNum:
+		if len(a) != len(b) {
+			return compareInt(len(a), len(b))
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		in   string
+		want semver
+		ok   bool
+	}{
+		{in: "v1.2.3", want: semver{Major: 1, Minor: 2, Patch: 3}, ok: true},
+		{in: "1.2", want: semver{Major: 1, Minor: 2}, ok: true},
+		{in: "V0.10.0-rc.1+sha.abc", want: semver{Minor: 10, Prerelease: []string{"rc", "1"}, Build: "sha.abc"}, ok: true},
+		{in: "1.0.0+001", want: semver{Major: 1, Build: "001"}, ok: true},
+		{in: "0.0.0", want: semver{}, ok: true},
+		{in: "01.2.3"},
+		{in: "1.02.3"},
+		{in: "1.2.00"},
+		{in: "1.2.3-rc.01"},
+		{in: "1.2.3.4"},
+		{in: "1.2.x"},
+		{in: "1.2.3-rc..1"},
+		{in: "1.2.3+"},
+		{in: "dev"},
+		{in: ""},
+	}
+	for _, tc := range tests {
+		got, ok := parseSemver(tc.in)
+		if ok != tc.ok {
+			t.Fatalf("%q: ok=%v want %v", tc.in, ok, tc.ok)
+		}
+		if !ok {
+			continue
+		}
+		if got.Major != tc.want.Major || got.Minor != tc.want.Minor || got.Patch != tc.want.Patch ||
+			strings.Join(got.Prerelease, ".") != strings.Join(tc.want.Prerelease, ".") || got.Build != tc.want.Build {
+			t.Fatalf("%q: got %+v want %+v", tc.in, got, tc.want)
+		}
+	}
+}
+
+// TestSemverPrecedenceOrder sorts the example list from the SemVer 2.0 spec
+// (section 11), with build metadata added where it must not matter.
+func TestSemverPrecedenceOrder(t *testing.T) {
+	want := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1+build.7",
+		"1.0.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+	}
+	got := append([]string(nil), want...)
+	sort.Slice(got, func(i, j int) bool { return got[i] > got[j] })
+	sort.SliceStable(got, func(i, j int) bool {
+		c, ok := compareSemver(got[i], got[j])
+		if !ok {
+			t.Fatalf("not a version: %s or %s", got[i], got[j])
+		}
+		return c < 0
+	})
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected order:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a      string
+		b      string
+		want   int
+		ok     bool
+		testID string
+	}{
+		{a: "v0.2.0", b: "v0.1.9", want: 1, ok: true, testID: "newer"},
+		{a: "0.1.0", b: "v0.1.0", want: 0, ok: true, testID: "same"},
+		{a: "v1.0.0", b: "dev", want: 0, ok: false, testID: "non-semver-local"},
+		{a: "v0.1.0", b: "v0.2.0", want: -1, ok: true, testID: "older"},
+		{a: "v1.0.0", b: "v1.0.0-rc.1", want: 1, ok: true, testID: "release-over-prerelease"},
+		{a: "v1.0.0-alpha", b: "v1.0.0-alpha.1", want: -1, ok: true, testID: "shorter-prerelease"},
+		{a: "v1.0.0-alpha.1", b: "v1.0.0-alpha.beta", want: -1, ok: true, testID: "numeric-before-alpha"},
+		{a: "v1.0.0-beta.2", b: "v1.0.0-beta.11", want: -1, ok: true, testID: "numeric-ids"},
+		{a: "v1.0.0-beta.11", b: "v1.0.0-rc.1", want: -1, ok: true, testID: "alpha-ids"},
+		{a: "v1.0.0+build.5", b: "v1.0.0+build.9", want: 0, ok: true, testID: "build-ignored"},
+		{a: "v1.0.0-rc.1+exp", b: "v1.0.0-rc.1", want: 0, ok: true, testID: "build-ignored-prerelease"},
+		{a: "v1.0.0-rc.1", b: "dev", want: 0, ok: false, testID: "prerelease-vs-dev"},
+		{a: "v1.0.0-01", b: "v1.0.0", want: 0, ok: false, testID: "leading-zero-prerelease"},
+		{a: "v1.0.0-", b: "v1.0.0", want: 0, ok: false, testID: "empty-prerelease"},
+		{a: "v01.2.3", b: "v1.2.3", want: 0, ok: false, testID: "leading-zero-core"},
+	}
+
+	for _, tc := range tests {
+		got, ok := compareSemver(tc.a, tc.b)
+		if ok != tc.ok {
+			t.Fatalf("%s: ok=%v want %v", tc.testID, ok, tc.ok)
+		}
+		if ok && got != tc.want {
+			t.Fatalf("%s: got=%d want=%d", tc.testID, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The Host block gitcrn writes sits between these markers so re-running init
+// can find and update exactly its own lines.
+const (
+	sshBlockBegin = "# BEGIN " + appName
+	sshBlockEnd   = "# END " + appName
+)
+
+// sshManagedKeys are the directives init owns. Anything else inside the
+// block (IdentityFile, ProxyJump, comments...) is the user's and is kept.
+var sshManagedKeys = map[string]bool{"hostname": true, "user": true, "port": true}
+
+// sshConfigChange is a planned rewrite of ~/.ssh/config, so init --dry-run
+// can show it and everything else can apply it the same way.
+type sshConfigChange struct {
+	Path   string
+	Exists bool
+	Old    string
+	New    string
+}
+
+func (c sshConfigChange) changed() bool {
+	return c.Old != c.New
+}
+
+func planSSHConfigChange(alias, host, user string, port int) (sshConfigChange, error) {
+	configPath, err := sshConfigPath()
+	if err != nil {
+		return sshConfigChange{}, err
+	}
+
+	change := sshConfigChange{Path: configPath}
+	data, err := os.ReadFile(configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return sshConfigChange{}, fmt.Errorf("читање %s: %w", configPath, err)
+	}
+	if err == nil {
+		change.Exists = true
+		change.Old = normalizeNewlines(string(data))
+	}
+
+	change.New = mergeSSHHostBlock(change.Old, alias, renderSSHHostBlock(alias, host, user, port))
+	return change, nil
+}
+
+// apply writes the new content after copying the current file to a
+// timestamped backup. It returns the backup path, empty for a new file.
+func (c sshConfigChange) apply() (string, error) {
+	if !c.changed() {
+		return "", nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o700); err != nil {
+		return "", fmt.Errorf("креирање .ssh директоријума: %w", err)
+	}
+
+	backup := ""
+	if c.Exists {
+		var err error
+		if backup, err = backupFile(c.Path, time.Now()); err != nil {
+			return "", err
+		}
+	}
+	if err := os.WriteFile(c.Path, []byte(c.New), 0o600); err != nil {
+		return "", fmt.Errorf("упис %s: %w", c.Path, err)
+	}
+	return backup, nil
+}
+
+// backupFile copies path to path.gitcrn-YYYYMMDD-HHMMSS.bak with 0600, adding
+// a counter if a backup from the same second already exists.
+func backupFile(path string, now time.Time) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("читање %s: %w", path, err)
+	}
+
+	base := fmt.Sprintf("%s.%s-%s", path, appName, now.Format("20060102-150405"))
+	backup := base + ".bak"
+	for i := 2; fileExists(backup); i++ {
+		backup = fmt.Sprintf("%s-%d.bak", base, i)
+	}
+	if err := os.WriteFile(backup, data, 0o600); err != nil {
+		return "", fmt.Errorf("резервна копија %s: %w", backup, err)
+	}
+	return backup, nil
+}
+
+func upsertSSHConfig(alias, host, user string, port int) (string, bool, error) {
+	change, err := planSSHConfigChange(alias, host, user, port)
+	if err != nil {
+		return "", false, err
+	}
+	if _, err := change.apply(); err != nil {
+		return "", false, err
+	}
+	return change.Path, change.changed(), nil
+}
+
+func renderSSHHostBlock(alias, host, user string, port int) string {
+	return strings.Join([]string{
+		fmt.Sprintf("Host %s", alias),
+		fmt.Sprintf("    HostName %s", host),
+		fmt.Sprintf("    User %s", user),
+		fmt.Sprintf("    Port %d", port),
+	}, "\n")
+}
+
+// mergeSSHHostBlock puts block, wrapped in the BEGIN/END markers, in place of
+// the managed block, or of an older unmarked Host block for alias, or at the
+// end. Directives the user added to the old block are carried over, and
+// lines outside it are left as they were.
+func mergeSSHHostBlock(content, alias, block string) string {
+	if strings.TrimSpace(content) == "" {
+		return wrapSSHHostBlock(block, nil) + "\n"
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	var (
+		out    []string
+		extras []string
+		at     = -1
+	)
+	for i := 0; i < len(lines); {
+		curr := strings.TrimSpace(lines[i])
+
+		if curr == sshBlockBegin {
+			end := i + 1
+			for end < len(lines) && strings.TrimSpace(lines[end]) != sshBlockEnd {
+				end++
+			}
+			if end == len(lines) {
+				// A BEGIN without END: drop the marker and let the Host
+				// block below be handled like an unmarked one.
+				i++
+				continue
+			}
+			extras = append(extras, sshBlockExtras(lines[i+1:end])...)
+			if at < 0 {
+				at = len(out)
+			}
+			i = end + 1
+			continue
+		}
+
+		if patterns, ok := parseHostLine(curr); ok && hostPatternMatches(patterns, alias) {
+			if rest := withoutHostPattern(lines[i], patterns, alias); rest != "" {
+				// A hand-written block shared with other hosts keeps its
+				// body; only the alias moves out to the managed block.
+				out = append(out, rest)
+				i++
+				continue
+			}
+			end := i + 1
+			for end < len(lines) && !isSSHBlockStart(lines[end]) {
+				end++
+			}
+			// Comments and blank lines just above the next block describe
+			// that block, not this one.
+			for end > i+1 && isBlankOrComment(lines[end-1]) {
+				end--
+			}
+			extras = append(extras, sshBlockExtras(lines[i+1:end])...)
+			if at < 0 {
+				at = len(out)
+			}
+			i = end
+			continue
+		}
+
+		out = append(out, lines[i])
+		i++
+	}
+
+	wrapped := strings.Split(wrapSSHHostBlock(block, extras), "\n")
+	if at < 0 {
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
+			out = append(out, "")
+		}
+		out = append(out, wrapped...)
+	} else {
+		out = append(out[:at], append(wrapped, out[at:]...)...)
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// withoutHostPattern rewrites a Host line without alias, keeping its
+// indentation and the other patterns. It returns "" when alias is the only
+// pattern.
+func withoutHostPattern(line string, patterns []string, alias string) string {
+	var kept []string
+	for _, p := range patterns {
+		if !strings.EqualFold(p, alias) {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	return indent + strings.Fields(line)[0] + " " + strings.Join(kept, " ")
+}
+
+func wrapSSHHostBlock(block string, extras []string) string {
+	lines := []string{sshBlockBegin, block}
+	lines = append(lines, extras...)
+	lines = append(lines, sshBlockEnd)
+	return strings.Join(lines, "\n")
+}
+
+// sshBlockExtras returns the lines of an old block body that init does not
+// manage, without surrounding blank lines.
+func sshBlockExtras(body []string) []string {
+	var extras []string
+	for _, raw := range body {
+		line := strings.TrimSpace(raw)
+		if _, isHost := parseHostLine(line); isHost {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) > 0 && sshManagedKeys[strings.ToLower(fields[0])] {
+			continue
+		}
+		if line == "" && (len(extras) == 0 || strings.TrimSpace(extras[len(extras)-1]) == "") {
+			continue
+		}
+		extras = append(extras, raw)
+	}
+	for len(extras) > 0 && strings.TrimSpace(extras[len(extras)-1]) == "" {
+		extras = extras[:len(extras)-1]
+	}
+	return extras
+}
+
+func isSSHBlockStart(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == sshBlockBegin {
+		return true
+	}
+	if _, ok := parseHostLine(line); ok {
+		return true
+	}
+	fields := strings.Fields(line)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "Match")
+}
+
+func isBlankOrComment(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, "#")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeSSHHostBlockKeepsUserDirectives(t *testing.T) {
+	block := renderSSHHostBlock("gitcrn", "100.91.132.35", "git", 222)
+	existing := strings.Join([]string{
+		"Host github.com",
+		"    HostName github.com",
+		"",
+		"Host gitcrn",
+		"    HostName old.example",
+		"    IdentityFile ~/.ssh/gitcrn",
+		"    # преко bastion-а",
+		"    ProxyJump bastion",
+		"    User old",
+		"    Port 2022",
+		"",
+		"# интерни сервер",
+		"Host internal",
+		"    HostName 10.0.0.5",
+		"",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"Host github.com",
+		"    HostName github.com",
+		"",
+		"# BEGIN gitcrn",
+		"Host gitcrn",
+		"    HostName 100.91.132.35",
+		"    User git",
+		"    Port 222",
+		"    IdentityFile ~/.ssh/gitcrn",
+		"    # преко bastion-а",
+		"    ProxyJump bastion",
+		"# END gitcrn",
+		"",
+		"# интерни сервер",
+		"Host internal",
+		"    HostName 10.0.0.5",
+		"",
+	}, "\n")
+	got := mergeSSHHostBlock(existing, "gitcrn", block)
+	if got != want {
+		t.Fatalf("unexpected merge:\n%s", got)
+	}
+
+	// Re-running init with another address keeps the user's lines and the
+	// rest of the file.
+	again := mergeSSHHostBlock(got, "gitcrn", renderSSHHostBlock("gitcrn", "100.91.140.2", "git", 222))
+	if strings.Replace(again, "100.91.140.2", "100.91.132.35", 1) != want {
+		t.Fatalf("re-init changed more than the managed lines:\n%s", again)
+	}
+	if mergeSSHHostBlock(got, "gitcrn", block) != got {
+		t.Fatal("merge should be idempotent")
+	}
+}
+
+func TestMergeSSHHostBlockAppendsMarkedBlock(t *testing.T) {
+	block := renderSSHHostBlock("gitcrn", "100.91.132.35", "git", 222)
+	got := mergeSSHHostBlock("Host github.com\n    User git\n", "gitcrn", block)
+	want := "Host github.com\n    User git\n\n# BEGIN gitcrn\n" + block + "\n# END gitcrn\n"
+	if got != want {
+		t.Fatalf("unexpected append:\n%s", got)
+	}
+	if got := mergeSSHHostBlock("", "gitcrn", block); got != "# BEGIN gitcrn\n"+block+"\n# END gitcrn\n" {
+		t.Fatalf("unexpected new file:\n%s", got)
+	}
+
+	// A BEGIN marker without END must not swallow the rest of the file.
+	broken := "# BEGIN gitcrn\nHost gitcrn\n    HostName old\nHost other\n    User x\n"
+	got = mergeSSHHostBlock(broken, "gitcrn", block)
+	if !strings.Contains(got, "# END gitcrn\nHost other\n    User x\n") || strings.Count(got, "# BEGIN gitcrn") != 1 {
+		t.Fatalf("unexpected repair:\n%s", got)
+	}
+}
+
+func TestMergeSSHHostBlockSharedHostLine(t *testing.T) {
+	block := renderSSHHostBlock("gitcrn", "100.91.132.35", "git", 222)
+	in := "Host gitcrn other-host\n    HostName 10.0.0.5\n    IdentityFile ~/.ssh/other\n"
+	got := mergeSSHHostBlock(in, "gitcrn", block)
+	want := "Host other-host\n    HostName 10.0.0.5\n    IdentityFile ~/.ssh/other\n\n# BEGIN gitcrn\n" + block + "\n# END gitcrn\n"
+	if got != want {
+		t.Fatalf("other-host settings must stay:\n%s", got)
+	}
+	if mergeSSHHostBlock(got, "gitcrn", block) != got {
+		t.Fatal("merge should be idempotent")
+	}
+}
+
+func TestSSHConfigChangeBacksUp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	change, err := planSSHConfigChange("gitcrn", "100.91.132.35", "git", 222)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup, err := change.apply(); err != nil || backup != "" {
+		t.Fatalf("new file needs no backup: %q, %v", backup, err)
+	}
+
+	change, _ = planSSHConfigChange("gitcrn", "100.91.140.2", "git", 222)
+	backup, err := change.apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, _ := os.ReadFile(backup)
+	if !strings.Contains(string(old), "100.91.132.35") || !strings.HasPrefix(filepath.Base(backup), "config.gitcrn-") {
+		t.Fatalf("unexpected backup %s:\n%s", backup, old)
+	}
+
+	change, _ = planSSHConfigChange("gitcrn", "100.91.140.2", "git", 222)
+	if change.changed() {
+		t.Fatal("same settings should not change the file")
+	}
+}
+
+func TestBackupFileAvoidsCollisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	first, err := backupFile(path, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := backupFile(path, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(first) != "config.gitcrn-20261018-093000.bak" || filepath.Base(second) != "config.gitcrn-20261018-093000-2.bak" {
+		t.Fatalf("unexpected backups: %s, %s", first, second)
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSSHIncludeDepth matches the limit OpenSSH puts on nested Include.
+const maxSSHIncludeDepth = 16
+
+// sshMultiValueKeys collect every value instead of keeping the first one.
+var sshMultiValueKeys = map[string]bool{
+	"identityfile":    true,
+	"certificatefile": true,
+	"localforward":    true,
+	"remoteforward":   true,
+	"dynamicforward":  true,
+	"sendenv":         true,
+	"setenv":          true,
+}
+
+// sshResolved is the configuration ssh would use for one host, evaluated
+// the way ssh_config(5) describes: files are read top to bottom, every
+// matching Host or Match block applies, and the first value of a keyword
+// wins.
+type sshResolved struct {
+	Settings map[string]string
+	Multi    map[string][]string
+	// Sources says where each setting came from, as "file:line".
+	Sources map[string]string
+	// Matched is true when a block other than "Host *" / "Match all" named
+	// the host, i.e. the host is configured at all.
+	Matched bool
+	// Unsupported lists Match criteria that cannot be evaluated without
+	// running commands (exec); such blocks are treated as not matching.
+	Unsupported []string
+}
+
+func (r sshResolved) get(key string) string {
+	return r.Settings[strings.ToLower(key)]
+}
+
+// identityFiles returns IdentityFile values in order, with ~ expanded.
+func (r sshResolved) identityFiles() []string {
+	var files []string
+	for _, f := range r.Multi["identityfile"] {
+		files = append(files, expandHomePath(strings.Trim(f, "\"")))
+	}
+	return files
+}
+
+type sshResolver struct {
+	host      string
+	localUser string
+	baseDir   string
+	result    sshResolved
+}
+
+// sshSystemConfigPath is the system-wide file ssh reads after the user's.
+func sshSystemConfigPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(fallback(os.Getenv("ProgramData"), `C:\ProgramData`), "ssh", "ssh_config")
+	}
+	return "/etc/ssh/ssh_config"
+}
+
+// resolveSSHHost evaluates the given config files for host in order, the way
+// ssh reads ~/.ssh/config and then the system file. Missing files are
+// skipped.
+func resolveSSHHost(host string, files ...string) (sshResolved, error) {
+	r := &sshResolver{
+		host:      host,
+		localUser: sshLocalUser(),
+		result: sshResolved{
+			Settings: map[string]string{},
+			Multi:    map[string][]string{},
+			Sources:  map[string]string{},
+		},
+	}
+	for _, file := range files {
+		// Relative Include paths are relative to the directory of the
+		// top-level file: ~/.ssh or /etc/ssh.
+		r.baseDir = filepath.Dir(file)
+		if err := r.readFile(file, 0); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return r.result, err
+		}
+	}
+	return r.result, nil
+}
+
+// sshLocalUser is the login name ssh uses when User is not set, without the
+// DOMAIN\ prefix Windows adds.
+func sshLocalUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	name := u.Username
+	if i := strings.LastIndex(name, `\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func (r *sshResolver) readFile(file string, depth int) error {
+	if depth > maxSSHIncludeDepth {
+		return fmt.Errorf("превише угњеждених Include (%s)", file)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	// Lines before the first Host or Match apply to every host.
+	active := true
+	for i, raw := range strings.Split(normalizeNewlines(string(data)), "\n") {
+		key, args := splitSSHConfigLine(raw)
+		if key == "" {
+			continue
+		}
+
+		switch key {
+		case "host":
+			active = r.matchHost(args)
+			continue
+		case "match":
+			active = r.matchCriteria(args)
+			continue
+		}
+		if !active {
+			continue
+		}
+
+		if key == "include" {
+			for _, pattern := range args {
+				if err := r.include(pattern, depth); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		source := fmt.Sprintf("%s:%d", file, i+1)
+		value := strings.Join(args, " ")
+		if sshMultiValueKeys[key] {
+			r.result.Multi[key] = append(r.result.Multi[key], value)
+			if _, ok := r.result.Sources[key]; !ok {
+				r.result.Sources[key] = source
+			}
+			continue
+		}
+		if _, ok := r.result.Settings[key]; !ok {
+			r.result.Settings[key] = r.expandTokens(key, value)
+			r.result.Sources[key] = source
+		}
+	}
+	return nil
+}
+
+// include reads every file matching pattern in lexical order, as ssh does.
+func (r *sshResolver) include(pattern string, depth int) error {
+	p := expandHomePath(strings.Trim(pattern, "\""))
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(r.baseDir, p)
+	}
+	matches, err := filepath.Glob(p)
+	if err != nil {
+		return fmt.Errorf("Include %s: %w", pattern, err)
+	}
+	sort.Strings(matches)
+	for _, m := range matches {
+		if info, err := os.Stat(m); err != nil || info.IsDir() {
+			continue
+		}
+		if err := r.readFile(m, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *sshResolver) matchHost(patterns []string) bool {
+	if !sshPatternsMatch(patterns, r.host) {
+		return false
+	}
+	if !(len(patterns) == 1 && patterns[0] == "*") {
+		r.result.Matched = true
+	}
+	return true
+}
+
+// matchCriteria evaluates a Match line. All criteria must hold; each can be
+// negated with "!".
+func (r *sshResolver) matchCriteria(args []string) bool {
+	named := false
+	for i := 0; i < len(args); i++ {
+		criterion := strings.ToLower(args[i])
+		negate := strings.HasPrefix(criterion, "!")
+		criterion = strings.TrimPrefix(criterion, "!")
+
+		var ok bool
+		switch criterion {
+		case "all":
+			ok = true
+		case "canonical":
+			// gitcrn never canonicalizes, so only the first pass exists.
+			ok = false
+		case "final":
+			ok = true
+		case "host", "originalhost", "user", "localuser", "exec", "localnetwork", "tagged":
+			if i+1 >= len(args) {
+				return false
+			}
+			i++
+			list := splitSSHPatternList(args[i])
+			switch criterion {
+			case "host":
+				ok = sshPatternsMatch(list, fallback(r.result.Settings["hostname"], r.host))
+				named = named || ok
+			case "originalhost":
+				ok = sshPatternsMatch(list, r.host)
+				named = named || ok
+			case "user":
+				ok = sshPatternsMatch(list, fallback(r.result.Settings["user"], r.localUser))
+			case "localuser":
+				ok = sshPatternsMatch(list, r.localUser)
+			default:
+				r.result.Unsupported = append(r.result.Unsupported, "Match "+criterion+" "+args[i])
+				return false
+			}
+		default:
+			r.result.Unsupported = append(r.result.Unsupported, "Match "+criterion)
+			return false
+		}
+		if ok == negate {
+			return false
+		}
+	}
+	if named {
+		r.result.Matched = true
+	}
+	return true
+}
+
+// expandTokens handles the %h and %% tokens in HostName, which is the only
+// place gitcrn cares about them.
+func (r *sshResolver) expandTokens(key, value string) string {
+	if key != "hostname" || !strings.Contains(value, "%") {
+		return value
+	}
+	return strings.NewReplacer("%%", "%", "%h", r.host).Replace(value)
+}
+
+// splitSSHConfigLine returns the lowercased keyword and its arguments.
+// ssh accepts both "Key value" and "Key=value", and double-quoted arguments
+// may contain spaces.
+func splitSSHConfigLine(raw string) (string, []string) {
+	line := strings.TrimSpace(raw)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return strings.ToLower(line), nil
+	}
+	key := strings.ToLower(line[:end])
+	rest := strings.TrimLeft(line[end:], " \t")
+	rest = strings.TrimLeft(strings.TrimPrefix(rest, "="), " \t")
+
+	var (
+		args  []string
+		cur   strings.Builder
+		quote bool
+		have  bool
+	)
+	for _, c := range rest {
+		switch {
+		case c == '"':
+			quote = !quote
+			have = true
+		case (c == ' ' || c == '\t') && !quote:
+			if have {
+				args = append(args, cur.String())
+				cur.Reset()
+				have = false
+			}
+		default:
+			cur.WriteRune(c)
+			have = true
+		}
+	}
+	if have {
+		args = append(args, cur.String())
+	}
+	return key, args
+}
+
+func splitSSHPatternList(list string) []string {
+	var patterns []string
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// sshPatternsMatch applies ssh_config pattern rules: any positive match and
+// no negated (!pattern) match.
+func sshPatternsMatch(patterns []string, host string) bool {
+	matched := false
+	for _, p := range patterns {
+		negated := strings.HasPrefix(p, "!")
+		p = strings.ToLower(strings.TrimPrefix(p, "!"))
+		ok, err := path.Match(p, strings.ToLower(host))
+		if err != nil || !ok {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// sshEffectiveConfig runs `ssh -G host`, which prints the configuration ssh
+// would use, including anything gitcrn cannot evaluate (Match exec). ssh
+// finds its config through the passwd home, not $HOME, so a config under a
+// different $HOME is passed with -F.
+func sshEffectiveConfig(configPath, host string, timeout time.Duration) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := []string{"-G", host}
+	if u, err := user.Current(); err == nil && u.HomeDir != "" && filepath.Join(u.HomeDir, ".ssh", "config") != configPath {
+		args = append([]string{"-F", configPath}, args...)
+	}
+	out, err := exec.CommandContext(ctx, "ssh", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, errors.New(firstOutputLine(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return parseSSHG(string(out)), nil
+}
+
+func parseSSHG(out string) map[string]string {
+	settings := map[string]string{}
+	for _, line := range strings.Split(normalizeNewlines(out), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(key)
+		if _, exists := settings[key]; !exists {
+			settings[key] = strings.TrimSpace(value)
+		}
+	}
+	return settings
+}
+
+// sshDisagreements compares what gitcrn resolved with `ssh -G` for the keys
+// that decide where gitcrn connects. ssh fills in defaults (port 22, the
+// local user, HostName = alias), so the comparison uses the same defaults.
+func sshDisagreements(resolved sshResolved, sshG map[string]string, alias, localUser string) []string {
+	want := map[string]string{
+		"hostname": fallback(resolved.get("hostname"), alias),
+		"user":     fallback(resolved.get("user"), localUser),
+		"port":     fallback(resolved.get("port"), "22"),
+	}
+	var diffs []string
+	for _, key := range []string{"hostname", "user", "port"} {
+		got, ok := sshG[key]
+		if !ok || (key == "user" && localUser == "" && resolved.get("user") == "") {
+			continue
+		}
+		if !strings.EqualFold(got, want[key]) {
+			diffs = append(diffs, fmt.Sprintf("%s: gitcrn %s, ssh -G %s", key, want[key], got))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSSHTestFile(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSSHPatternsMatch(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		want     bool
+	}{
+		{[]string{"*"}, true},
+		{[]string{"git*"}, true},
+		{[]string{"gitcr?"}, true},
+		{[]string{"*", "!gitcrn"}, false},
+		{[]string{"github.com"}, false},
+		{[]string{"!other"}, false},
+	}
+	for _, c := range cases {
+		if got := sshPatternsMatch(c.patterns, "gitcrn"); got != c.want {
+			t.Fatalf("sshPatternsMatch(%v) = %v, want %v", c.patterns, got, c.want)
+		}
+	}
+}
+
+func TestSplitSSHConfigLine(t *testing.T) {
+	cases := []struct {
+		line string
+		key  string
+		args []string
+	}{
+		{"  HostName 10.0.0.1", "hostname", []string{"10.0.0.1"}},
+		{"Port=2222", "port", []string{"2222"}},
+		{"User = git", "user", []string{"git"}},
+		{`IdentityFile "~/.ssh/my key"`, "identityfile", []string{"~/.ssh/my key"}},
+		{"Host gitcrn git*  !github", "host", []string{"gitcrn", "git*", "!github"}},
+		{"# HostName nope", "", nil},
+	}
+	for _, c := range cases {
+		key, args := splitSSHConfigLine(c.line)
+		if key != c.key || strings.Join(args, "|") != strings.Join(c.args, "|") {
+			t.Fatalf("splitSSHConfigLine(%q) = %q %q", c.line, key, args)
+		}
+	}
+}
+
+func TestResolveSSHHost(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	sshDir := filepath.Join(home, ".ssh")
+	config := filepath.Join(sshDir, "config")
+
+	writeSSHTestFile(t, config,
+		"Include conf.d/*.conf",
+		"",
+		"Host !github.com git*",
+		"    User git",
+		"    IdentityFile ~/.ssh/git_ed25519",
+		"",
+		"Host gitcrn",
+		"    HostName 100.91.132.35",
+		"    User ignored",
+		"",
+		"Match originalhost gitcrn !user root",
+		"    Port 222",
+		"",
+		"Match exec \"test -f /nonexistent\"",
+		"    Port 1",
+		"",
+		"Host *",
+		"    Port 22",
+		"    IdentityFile ~/.ssh/id_ed25519",
+	)
+	writeSSHTestFile(t, filepath.Join(sshDir, "conf.d", "10-base.conf"),
+		"Host gitcrn",
+		"    ProxyJump bastion",
+		"    Include ~/.ssh/nested",
+	)
+	writeSSHTestFile(t, filepath.Join(sshDir, "nested"),
+		"ServerAliveInterval 15",
+	)
+	system := filepath.Join(t.TempDir(), "ssh_config")
+	writeSSHTestFile(t, system,
+		"Host *",
+		"    ServerAliveInterval 60",
+		"    Compression yes",
+	)
+
+	r, err := resolveSSHHost("gitcrn", config, system, filepath.Join(home, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"hostname":            "100.91.132.35",
+		"user":                "git",
+		"port":                "222",
+		"proxyjump":           "bastion",
+		"serveraliveinterval": "15",
+		"compression":         "yes",
+	}
+	for key, value := range want {
+		if got := r.get(key); got != value {
+			t.Fatalf("%s = %q, want %q (all: %v)", key, got, value, r.Settings)
+		}
+	}
+	if !r.Matched {
+		t.Fatal("gitcrn should be matched")
+	}
+	if !strings.HasSuffix(r.Sources["proxyjump"], filepath.Join("conf.d", "10-base.conf")+":2") {
+		t.Fatalf("unexpected source: %q", r.Sources["proxyjump"])
+	}
+	ids := r.identityFiles()
+	if len(ids) != 2 || ids[0] != filepath.Join(home, ".ssh", "git_ed25519") || ids[1] != filepath.Join(home, ".ssh", "id_ed25519") {
+		t.Fatalf("unexpected identity files: %v", ids)
+	}
+	if len(r.Unsupported) != 1 || !strings.HasPrefix(r.Unsupported[0], "Match exec") {
+		t.Fatalf("Match exec should be reported: %v", r.Unsupported)
+	}
+
+	other, err := resolveSSHHost("github.com", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Matched || other.get("user") != "" || other.get("port") != "22" {
+		t.Fatalf("github.com should only get Host * defaults: %+v", other)
+	}
+}
+
+func TestResolveSSHHostMatchHostUsesHostName(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config")
+	writeSSHTestFile(t, config,
+		"Host gitcrn",
+		"    HostName %h.tail1234.ts.net",
+		"Match host *.ts.net",
+		"    User git",
+	)
+	r, err := resolveSSHHost("gitcrn", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.get("hostname") != "gitcrn.tail1234.ts.net" || r.get("user") != "git" {
+		t.Fatalf("unexpected settings: %v", r.Settings)
+	}
+}
+
+func TestResolveSSHHostIncludeLoop(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config")
+	writeSSHTestFile(t, config, "Include "+config)
+	if _, err := resolveSSHHost("gitcrn", config); err == nil {
+		t.Fatal("recursive Include should fail")
+	}
+}
+
+func TestSSHDisagreements(t *testing.T) {
+	resolved := sshResolved{Settings: map[string]string{"hostname": "100.91.132.35", "port": "222"}}
+	sshG := parseSSHG("user me\nhostname 100.91.132.35\nport 2222\n")
+	diffs := sshDisagreements(resolved, sshG, "gitcrn", "me")
+	if len(diffs) != 1 || !strings.HasPrefix(diffs[0], "port: gitcrn 222") {
+		t.Fatalf("unexpected diffs: %v", diffs)
+	}
+	sshG["port"] = "222"
+	if diffs := sshDisagreements(resolved, sshG, "gitcrn", "me"); len(diffs) != 0 {
+		t.Fatalf("expected agreement: %v", diffs)
+	}
+}
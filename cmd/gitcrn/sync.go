@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// remoteResult is the outcome of one git push or pull.
+type remoteResult struct {
+	Remote string
+	Output string
+	Err    error
+	// Skipped is set for remotes not tried after an earlier pull failed.
+	Skipped bool
+}
+
+// gitIn runs git in dir and returns its combined output.
+func gitIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// nativePush does what push.sh does, from Go: stage everything, commit if
+// there is something to commit, then push to every remote at once. A dead
+// remote only costs its own timeout and does not stop the others. timeout
+// overrides push_timeout when non-zero.
+func nativePush(w io.Writer, root string, rc repoConfig, timeout time.Duration) error {
+	if len(rc.PushRemotes) == 0 {
+		return fmt.Errorf("push_remotes није подешен у %s; покрени: %s remake --push", repoConfigName, appName)
+	}
+	for _, remote := range rc.RequiredRemotes {
+		if !containsString(rc.PushRemotes, remote) {
+			return fmt.Errorf("обавезан remote %s није у push_remotes", remote)
+		}
+	}
+	if timeout <= 0 {
+		timeout = rc.PushTimeout
+	}
+	if timeout <= 0 {
+		timeout = defaultPushTimeout
+	}
+
+	if out, err := gitIn(root, "add", "."); err != nil {
+		return fmt.Errorf("git add: %s", firstOutputLine(fallback(out, err.Error())))
+	}
+	cmd := exec.Command("git", "diff", "--cached", "--quiet")
+	cmd.Dir = root
+	if cmd.Run() == nil {
+		fmt.Fprintln(w, "Нема измена за commit, прескачем commit.")
+	} else {
+		message := fallback(rc.Message, defaultCommitMsg)
+		out, err := gitIn(root, "commit", "-m", message)
+		if err != nil {
+			return fmt.Errorf("git commit: %s", firstOutputLine(fallback(out, err.Error())))
+		}
+		fmt.Fprintln(w, firstOutputLine(out))
+	}
+
+	results := pushAll(root, rc.Branch, rc.PushRemotes, rc.required(), timeout)
+	return reportPushResults(w, results)
+}
+
+// nativePull pulls from each remote in turn. Unlike push it stops at the
+// first failure: after a conflict the next pull would only fail the same way.
+func nativePull(w io.Writer, root string, rc repoConfig) error {
+	if len(rc.PullRemotes) == 0 {
+		return fmt.Errorf("pull_remotes није подешен у %s; покрени: %s remake --pull", repoConfigName, appName)
+	}
+
+	var results []remoteResult
+	failed := false
+	for _, remote := range rc.PullRemotes {
+		if failed {
+			results = append(results, remoteResult{Remote: remote, Skipped: true})
+			continue
+		}
+		args := []string{"pull", remote}
+		if rc.Branch != "" {
+			args = append(args, rc.Branch)
+		}
+		out, err := gitIn(root, args...)
+		results = append(results, remoteResult{Remote: remote, Output: out, Err: err})
+		failed = err != nil
+	}
+	return reportRemoteResults(w, "pull", rc.Branch, results)
+}
+
+// pushResult is one row of the push summary.
+type pushResult struct {
+	Remote   string `json:"remote"`
+	Ref      string `json:"ref"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Required bool   `json:"required"`
+	Output   string `json:"-"`
+}
+
+// Push statuses, from git push --porcelain flags plus timeout and error.
+const (
+	pushOK       = "ok"
+	pushNew      = "new"
+	pushForced   = "forced"
+	pushUpToDate = "up-to-date"
+	pushDeleted  = "deleted"
+	pushRejected = "rejected"
+	pushTimeout  = "timeout"
+	pushError    = "error"
+)
+
+func (r pushResult) failed() bool {
+	return r.Status == pushRejected || r.Status == pushTimeout || r.Status == pushError
+}
+
+// pushAll pushes branch to every remote concurrently, each with its own
+// timeout, and returns the results in the order of remotes.
+func pushAll(root, branch string, remotes, required []string, timeout time.Duration) []pushResult {
+	local := ""
+	if sha, err := gitIn(root, "rev-parse", "--short", fallback(branch, "HEAD")); err == nil {
+		local = sha
+	}
+
+	results := make([]pushResult, len(remotes))
+	var wg sync.WaitGroup
+	for i, remote := range remotes {
+		wg.Add(1)
+		go func(i int, remote string) {
+			defer wg.Done()
+			r := pushRemote(root, remote, branch, timeout)
+			r.Required = containsString(required, remote)
+			if r.New == "" && (r.Status == pushNew || r.Status == pushUpToDate) {
+				r.New = local
+			}
+			results[i] = r
+		}(i, remote)
+	}
+	wg.Wait()
+	return results
+}
+
+func pushRemote(root, remote, branch string, timeout time.Duration) pushResult {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := []string{"push", "--porcelain", remote}
+	if branch != "" {
+		args = append(args, branch)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = root
+	// Parallel pushes cannot share a terminal for credential prompts.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	// ssh started by git may keep the pipes open after git is killed.
+	cmd.WaitDelay = 2 * time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	r := parsePushPorcelain(stdout.String())
+	r.Remote = remote
+	r.Output = strings.TrimSpace(stderr.String())
+	if r.Ref == "" {
+		r.Ref = fallback(branch, "HEAD")
+	}
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		r.Status = pushTimeout
+		r.Detail = "после " + timeout.String()
+	case err != nil && r.Status == "":
+		r.Status = pushError
+		r.Detail = firstOutputLine(fallback(r.Output, err.Error()))
+	case err != nil && !r.failed():
+		// Another ref was rejected, or git failed after updating this one.
+		r.Status = pushError
+		r.Detail = firstOutputLine(fallback(r.Output, err.Error()))
+	}
+	return r
+}
+
+// parsePushPorcelain reads the ref line of `git push --porcelain`:
+// "<flag>\t<from>:<to>\t<summary> (<reason>)".
+func parsePushPorcelain(out string) pushResult {
+	var r pushResult
+	for _, line := range strings.Split(normalizeNewlines(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 || len(fields[0]) != 1 {
+			continue
+		}
+		_, to, _ := strings.Cut(fields[1], ":")
+		r.Ref = strings.TrimPrefix(to, "refs/heads/")
+		summary := fields[2]
+		if i := strings.Index(summary, " ("); i >= 0 && strings.HasSuffix(summary, ")") {
+			r.Detail = summary[i+2 : len(summary)-1]
+			summary = summary[:i]
+		}
+		if old, new, ok := strings.Cut(summary, ".."); ok {
+			r.Old, r.New = old, strings.TrimPrefix(new, ".")
+		}
+
+		switch fields[0] {
+		case " ":
+			r.Status = pushOK
+		case "+":
+			r.Status = pushForced
+		case "*":
+			r.Status = pushNew
+		case "=":
+			r.Status = pushUpToDate
+		case "-":
+			r.Status = pushDeleted
+		case "!":
+			r.Status = pushRejected
+		}
+		break
+	}
+	return r
+}
+
+// reportPushResults prints the summary table (or JSON with --json) and git's
+// error output for each failed remote. Only required remotes make it fail.
+func reportPushResults(w io.Writer, results []pushResult) error {
+	if jsonOutput() {
+		if err := writeJSON(os.Stdout, results); err != nil {
+			return err
+		}
+	} else {
+		t := newTableWriter(w, "REMOTE", "REF", "ПРОМЕНА", "РЕЗУЛТАТ")
+		for _, r := range results {
+			t.Row(r.Remote, r.Ref, pushChange(r), t.Cell(pushResultText(r), 70))
+		}
+		t.Flush()
+	}
+
+	var requiredFailed, optionalFailed []string
+	for _, r := range results {
+		if !r.failed() {
+			continue
+		}
+		if r.Output != "" && r.Status != pushTimeout {
+			fmt.Fprintf(humanOut(), "\n%s:\n", r.Remote)
+			for _, line := range strings.Split(r.Output, "\n") {
+				fmt.Fprintln(humanOut(), strings.TrimRight("  "+line, " "))
+			}
+		}
+		if r.Required {
+			requiredFailed = append(requiredFailed, r.Remote)
+		} else {
+			optionalFailed = append(optionalFailed, r.Remote)
+		}
+	}
+	if len(optionalFailed) > 0 {
+		fmt.Fprintln(os.Stderr, colorize("Упозорење: push није успео за необавезне remote-е: "+strings.Join(optionalFailed, ", "), ansiYellow, stderrColor))
+	}
+	if len(requiredFailed) > 0 {
+		return fmt.Errorf("push није успео за обавезне remote-е: %s", strings.Join(requiredFailed, ", "))
+	}
+	return nil
+}
+
+func pushChange(r pushResult) string {
+	switch {
+	case r.Old != "" && r.New != "":
+		return r.Old + ".." + r.New
+	case r.Status == pushNew && r.New != "":
+		return "(нова).." + r.New
+	case r.New != "":
+		return r.New
+	default:
+		return "-"
+	}
+}
+
+func pushResultText(r pushResult) string {
+	text := map[string]string{
+		pushOK:       "ok",
+		pushNew:      "ok, нова грана",
+		pushForced:   "ok, force",
+		pushUpToDate: "без промена",
+		pushDeleted:  "обрисано",
+		pushRejected: "одбијено",
+		pushTimeout:  "timeout",
+		pushError:    "грешка",
+	}[r.Status]
+	if r.Detail != "" {
+		text += ": " + r.Detail
+	}
+	if r.failed() {
+		color := ansiYellow
+		if r.Required {
+			color = ansiRed
+		}
+		return colorize(text, color, stdoutColor && !machineOutput())
+	}
+	return colorize(text, ansiGreen, stdoutColor && !machineOutput())
+}
+
+// reportRemoteResults prints one line per remote, with git's output for the
+// failed ones, and returns an error naming the remotes that failed.
+func reportRemoteResults(w io.Writer, op, branch string, results []remoteResult) error {
+	ref := fallback(branch, "HEAD")
+	var failed []string
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			fmt.Fprintf(w, "%s %s %s %s: прескочено\n", colorize("[SKIP]", ansiYellow, stdoutColor), op, r.Remote, ref)
+		case r.Err != nil:
+			failed = append(failed, r.Remote)
+			fmt.Fprintf(w, "%s %s %s %s\n", colorize("[FAIL]", ansiRed, stdoutColor), op, r.Remote, ref)
+			for _, line := range strings.Split(fallback(r.Output, r.Err.Error()), "\n") {
+				fmt.Fprintln(w, "  "+line)
+			}
+		default:
+			fmt.Fprintf(w, "%s %s %s %s\n", colorize("[OK]", ansiGreen, stdoutColor), op, r.Remote, ref)
+			if op == "pull" && r.Output != "" {
+				// The last line is the summary ("Already up to date.", "Fast-forward"...).
+				lines := strings.Split(r.Output, "\n")
+				fmt.Fprintln(w, "  "+strings.TrimSpace(lines[len(lines)-1]))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%s није успео за: %s", op, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// syncOptions are the push/pull flags.
+type syncOptions struct {
+	Script bool
+	// Timeout and Required override push_timeout and required_remotes.
+	Timeout  time.Duration
+	Required []string
+}
+
+// runSync runs push or pull natively when the repo has .gitcrn.toml and
+// falls back to the generated push.sh/pull.sh (or .ps1) otherwise.
+func runSync(op string, opts syncOptions) error {
+	if !opts.Script {
+		if root, err := gitRepoRoot(); err == nil {
+			rc, found, err := loadRepoConfig(root)
+			if err != nil {
+				return err
+			}
+			if found {
+				selectSyncEndpoint()
+				if op == "push" {
+					if len(opts.Required) > 0 {
+						rc.RequiredRemotes = opts.Required
+					}
+					return nativePush(humanOut(), root, rc, opts.Timeout)
+				}
+				return nativePull(humanOut(), root, rc)
+			}
+		}
+	}
+	path, cmd, err := generatedScript(op)
+	if err != nil {
+		return err
+	}
+	selectSyncEndpoint()
+	return runGeneratedScript(path, cmd)
+}
+
+// selectSyncEndpoint picks the endpoint once runSync knows there is
+// something to push or pull, so a bad invocation never probes the network.
+func selectSyncEndpoint() {
+	if cfg, err := loadAppConfig(); err == nil {
+		autoSelectEndpoint(cfg)
+	}
+}
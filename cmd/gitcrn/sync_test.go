@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gitTestRepo creates a work tree with a commit identity and bare remotes
+// next to it.
+func gitTestRepo(t *testing.T, remotes ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git није у PATH-у")
+	}
+	dir := t.TempDir()
+	run := func(dir string, args ...string) {
+		t.Helper()
+		if out, err := gitIn(dir, args...); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	work := filepath.Join(dir, "work")
+	run(dir, "init", "-q", "-b", "main", work)
+	run(work, "config", "user.email", "test@example.com")
+	run(work, "config", "user.name", "test")
+	run(work, "config", "commit.gpgsign", "false")
+	for _, r := range remotes {
+		bare := filepath.Join(dir, r+".git")
+		run(dir, "init", "-q", "--bare", bare)
+		run(work, "remote", "add", r, bare)
+	}
+	return work
+}
+
+func TestNativePushContinuesPastFailures(t *testing.T) {
+	work := gitTestRepo(t, "gitcrn", "backup")
+	if _, err := gitIn(work, "remote", "add", "dead", filepath.Join(t.TempDir(), "missing.git")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := nativePush(&out, work, repoConfig{Branch: "main", Message: "први", PushRemotes: []string{"dead", "gitcrn", "backup"}}, 0)
+	if err == nil || !strings.Contains(err.Error(), "dead") || strings.Contains(err.Error(), "gitcrn") {
+		t.Fatalf("expected only dead to fail: %v\n%s", err, out.String())
+	}
+	for _, remote := range []string{"gitcrn", "backup"} {
+		bare := filepath.Join(filepath.Dir(work), remote+".git")
+		if msg, err := gitIn(bare, "log", "-1", "--format=%s", "main"); err != nil || msg != "први" {
+			t.Fatalf("%s did not get the commit: %q, %v", remote, msg, err)
+		}
+	}
+
+	out.Reset()
+	if err := nativePush(&out, work, repoConfig{Branch: "main", PushRemotes: []string{"gitcrn"}}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Нема измена за commit") {
+		t.Fatalf("clean tree should skip the commit:\n%s", out.String())
+	}
+}
+
+func TestNativePushOptionalRemotes(t *testing.T) {
+	work := gitTestRepo(t, "gitcrn")
+	// A remote that accepts the connection and never answers.
+	t.Setenv("GIT_SSH_COMMAND", "sh -c 'sleep 10' --")
+	if _, err := gitIn(work, "remote", "add", "github", "ssh://example.invalid/repo.git"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rc := repoConfig{Branch: "main", PushRemotes: []string{"github", "gitcrn"}, RequiredRemotes: []string{"gitcrn"}}
+	var out bytes.Buffer
+	start := time.Now()
+	if err := nativePush(&out, work, rc, 500*time.Millisecond); err != nil {
+		t.Fatalf("an optional remote must not fail the push: %v\n%s", err, out.String())
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("timeout was not enforced: %s", elapsed)
+	}
+	for _, want := range []string{"github", "timeout", "gitcrn", "ok, нова грана"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("summary is missing %q:\n%s", want, out.String())
+		}
+	}
+
+	rc.RequiredRemotes = nil
+	out.Reset()
+	err := nativePush(&out, work, rc, 500*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "github") {
+		t.Fatalf("a required remote must fail the push: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "без промена") {
+		t.Fatalf("second push should be up to date on gitcrn:\n%s", out.String())
+	}
+
+	rc.RequiredRemotes = []string{"missing"}
+	if err := nativePush(&out, work, rc, time.Second); err == nil {
+		t.Fatal("a required remote outside push_remotes must be rejected")
+	}
+}
+
+func TestParsePushPorcelain(t *testing.T) {
+	out := "To /tmp/gitcrn.git\n \trefs/heads/main:refs/heads/main\t1a2b3c4..5d6e7f8\nDone\n"
+	if r := parsePushPorcelain(out); r.Status != pushOK || r.Ref != "main" || r.Old != "1a2b3c4" || r.New != "5d6e7f8" {
+		t.Fatalf("unexpected fast-forward: %+v", r)
+	}
+
+	out = "To gitcrn:vltc/repo.git\n!\trefs/heads/main:refs/heads/main\t[rejected] (fetch first)\nDone\n"
+	if r := parsePushPorcelain(out); r.Status != pushRejected || r.Detail != "fetch first" || !r.failed() {
+		t.Fatalf("unexpected rejection: %+v", r)
+	}
+
+	out = "+\trefs/heads/dev:refs/heads/dev\t1a2b3c4...5d6e7f8 (forced update)\n"
+	if r := parsePushPorcelain(out); r.Status != pushForced || r.Old != "1a2b3c4" || r.New != "5d6e7f8" {
+		t.Fatalf("unexpected forced update: %+v", r)
+	}
+
+	if r := parsePushPorcelain("*\trefs/heads/main:refs/heads/main\t[new branch]\n"); r.Status != pushNew || r.Old != "" {
+		t.Fatalf("unexpected new branch: %+v", r)
+	}
+}
+
+func TestNativePullStopsAtFirstFailure(t *testing.T) {
+	work := gitTestRepo(t, "gitcrn")
+	var out bytes.Buffer
+	err := nativePull(&out, work, repoConfig{Branch: "main", PullRemotes: []string{"missing", "gitcrn"}})
+	if err == nil || !strings.Contains(out.String(), "[SKIP]") {
+		t.Fatalf("expected failure and a skipped remote: %v\n%s", err, out.String())
+	}
+}
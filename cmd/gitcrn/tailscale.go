@@ -0,0 +1,437 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	tailscaleStatusTimeout = 5 * time.Second
+	tailscaleStateRunning  = "Running"
+)
+
+// tailscaleStatus is the subset of `tailscale status --json` that gitcrn
+// reads. Unknown fields are ignored, so newer clients keep working.
+type tailscaleStatus struct {
+	Version        string                    `json:"Version"`
+	BackendState   string                    `json:"BackendState"`
+	AuthURL        string                    `json:"AuthURL"`
+	Self           *tailscalePeer            `json:"Self"`
+	MagicDNSSuffix string                    `json:"MagicDNSSuffix"`
+	CurrentTailnet *tailscaleTailnet         `json:"CurrentTailnet"`
+	Peer           map[string]*tailscalePeer `json:"Peer"`
+}
+
+type tailscaleTailnet struct {
+	Name           string `json:"Name"`
+	MagicDNSSuffix string `json:"MagicDNSSuffix"`
+}
+
+type tailscalePeer struct {
+	HostName     string   `json:"HostName"`
+	DNSName      string   `json:"DNSName"`
+	OS           string   `json:"OS"`
+	TailscaleIPs []string `json:"TailscaleIPs"`
+	// CurAddr is the ip:port of a direct (peer-to-peer) path; empty means
+	// traffic, if any, goes through the DERP server named in Relay.
+	CurAddr string `json:"CurAddr"`
+	Relay   string `json:"Relay"`
+	Online  bool   `json:"Online"`
+	Active  bool   `json:"Active"`
+}
+
+func parseTailscaleStatus(data []byte) (tailscaleStatus, error) {
+	var st tailscaleStatus
+	if err := json.Unmarshal(data, &st); err != nil {
+		return tailscaleStatus{}, fmt.Errorf("не могу да прочитам tailscale status: %w", err)
+	}
+	if st.BackendState == "" {
+		return tailscaleStatus{}, errors.New("tailscale status нема BackendState")
+	}
+	return st, nil
+}
+
+// readTailscaleStatus runs `tailscale status --json`. The command still
+// prints JSON when the backend is stopped; it fails when tailscaled itself
+// is not reachable.
+func readTailscaleStatus() (tailscaleStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tailscaleStatusTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "tailscale", "status", "--json").Output()
+	if err != nil && len(out) == 0 {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return tailscaleStatus{}, fmt.Errorf("tailscale status: %s", firstOutputLine(string(exitErr.Stderr)))
+		}
+		return tailscaleStatus{}, fmt.Errorf("tailscale status: %w", err)
+	}
+	return parseTailscaleStatus(out)
+}
+
+func (s tailscaleStatus) running() bool {
+	return s.BackendState == tailscaleStateRunning
+}
+
+// stateHint tells the user how to get from the current backend state to
+// Running.
+func (s tailscaleStatus) stateHint() string {
+	switch s.BackendState {
+	case "NeedsLogin":
+		if s.AuthURL != "" {
+			return "tailscale login (или отвори " + s.AuthURL + ")"
+		}
+		return "tailscale login"
+	case "NeedsMachineAuth":
+		return "одобри овај уређај у Tailscale admin конзоли"
+	case "Starting":
+		return "сачекај да се Tailscale повеже или покрени tailscale up"
+	default:
+		return "tailscale up"
+	}
+}
+
+func (s tailscaleStatus) tailnetName() string {
+	if s.CurrentTailnet != nil {
+		return s.CurrentTailnet.Name
+	}
+	return ""
+}
+
+// findPeer looks up host by Tailscale IP, full MagicDNS name, short MagicDNS
+// name or machine name. Self is included so a server can check itself.
+func (s tailscaleStatus) findPeer(host string) (*tailscalePeer, bool) {
+	host = strings.TrimSuffix(strings.TrimSpace(host), ".")
+	if host == "" {
+		return nil, false
+	}
+
+	keys := make([]string, 0, len(s.Peer))
+	for k := range s.Peer {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	peers := make([]*tailscalePeer, 0, len(keys)+1)
+	for _, k := range keys {
+		if s.Peer[k] != nil {
+			peers = append(peers, s.Peer[k])
+		}
+	}
+	if s.Self != nil {
+		peers = append(peers, s.Self)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, p := range peers {
+			for _, addr := range p.TailscaleIPs {
+				if peerIP := net.ParseIP(addr); peerIP != nil && peerIP.Equal(ip) {
+					return p, true
+				}
+			}
+		}
+		return nil, false
+	}
+
+	for _, p := range peers {
+		if strings.EqualFold(p.fqdn(), host) || strings.EqualFold(p.shortName(), host) || strings.EqualFold(p.HostName, host) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (p *tailscalePeer) fqdn() string {
+	return strings.TrimSuffix(p.DNSName, ".")
+}
+
+func (p *tailscalePeer) shortName() string {
+	name, _, _ := strings.Cut(p.fqdn(), ".")
+	return name
+}
+
+func (p *tailscalePeer) displayName() string {
+	if name := p.shortName(); name != "" {
+		return name
+	}
+	return p.HostName
+}
+
+func (p *tailscalePeer) ipv4() string {
+	for _, addr := range p.TailscaleIPs {
+		if ip := net.ParseIP(addr); ip != nil && ip.To4() != nil {
+			return addr
+		}
+	}
+	if len(p.TailscaleIPs) > 0 {
+		return p.TailscaleIPs[0]
+	}
+	return ""
+}
+
+func (p *tailscalePeer) direct() bool {
+	return p.CurAddr != ""
+}
+
+// connection describes the path to the peer: direct with the endpoint, via
+// DERP with the relay region, or idle when there is no traffic yet.
+func (p *tailscalePeer) connection() string {
+	switch {
+	case p.direct():
+		return "директно (" + p.CurAddr + ")"
+	case p.Active && p.Relay != "":
+		return "преко DERP relay-а (" + p.Relay + ")"
+	case p.Relay != "":
+		return "нема активне везе (DERP " + p.Relay + ")"
+	default:
+		return "нема активне везе"
+	}
+}
+
+// isTailnetAddress reports whether host is a Tailscale IP (100.64.0.0/10,
+// fd7a:115c:a1e0::/48) or a MagicDNS name.
+func isTailnetAddress(host string) bool {
+	host = strings.TrimSuffix(strings.TrimSpace(host), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		for _, cidr := range []string{"100.64.0.0/10", "fd7a:115c:a1e0::/48"} {
+			if _, n, _ := net.ParseCIDR(cidr); n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(host), ".ts.net")
+}
+
+// tailnetError explains why host cannot be reached over the tailnet, or
+// returns nil. Hosts outside the tailnet (LAN, public DNS) are not judged.
+func tailnetError(st tailscaleStatus, host string) error {
+	peer, found := st.findPeer(host)
+	if !found && !isTailnetAddress(host) {
+		return nil
+	}
+
+	if !st.running() {
+		return fmt.Errorf("tailnet није повезан (Tailscale стање: %s), %s није доступан; %s", st.BackendState, host, st.stateHint())
+	}
+	if !found {
+		return fmt.Errorf("%s није у tailnet-у; провери ssh_host у config.toml или tailscale status", host)
+	}
+	if !peer.Online {
+		return fmt.Errorf("%s (%s) је offline у tailnet-у; укључи сервер или провери Tailscale на њему", peer.displayName(), peer.ipv4())
+	}
+	return nil
+}
+
+// ensureTailnetUp fails early with a clear message instead of letting ssh or
+// git time out when host sits on a tailnet that is down. When tailscale is
+// not installed or host is not a tailnet address, it does nothing.
+func ensureTailnetUp(host string) error {
+	if _, err := exec.LookPath("tailscale"); err != nil {
+		return nil
+	}
+	st, err := readTailscaleStatus()
+	if err != nil {
+		if isTailnetAddress(host) {
+			return fmt.Errorf("tailnet није доступан (%v); покрени tailscaled па tailscale up", err)
+		}
+		return nil
+	}
+	return tailnetError(st, host)
+}
+
+// repoHost is the host that clone and push actually connect to.
+func repoHost(cfg appConfig, forceHTTPS bool) string {
+	if forceHTTPS || cfg.Protocol == "https" {
+		if u, err := url.Parse(resolveServerURL(cfg)); err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+	return cfg.SSHHost
+}
+
+func doctorCheckTailscaleStatus(report *doctorReport) {
+	st, err := readTailscaleStatus()
+	cfg, _ := loadAppConfig()
+	if !doctorCheckTailscaleState(report, st, err, cfg.SSHHost) {
+		return
+	}
+	// A LAN or public endpoint is expected to differ from the tailnet node.
+	if cfg.TailscaleNode != "" && (cfg.Endpoint == "" || isTailnetAddress(cfg.SSHHost)) {
+		doctorCheckTailscaleNode(report, st, cfg)
+	}
+	host := cfg.SSHHost
+	peer, found := st.findPeer(host)
+	if !found {
+		if isTailnetAddress(host) {
+			report.fail("tailscale.peer", "Gitea сервер у tailnet-у", host+" није у овом tailnet-у", "провери ssh_host у config.toml или tailscale status")
+		}
+		return
+	}
+	if !peer.Online {
+		report.fail("tailscale.peer", "Gitea сервер у tailnet-у", fmt.Sprintf("%s (%s) је offline", peer.displayName(), peer.ipv4()), "укључи сервер или провери Tailscale на њему")
+		return
+	}
+	report.ok("tailscale.peer", "Gitea сервер у tailnet-у", fmt.Sprintf("%s (%s) online", peer.displayName(), peer.ipv4()))
+
+	if peer.Active && !peer.direct() && peer.Relay != "" {
+		report.warn("tailscale.path", "Tailscale веза", peer.connection(),
+			"директна веза је бржа; tailscale netcheck показује да ли UDP пролази кроз NAT/firewall")
+		return
+	}
+	report.ok("tailscale.path", "Tailscale веза", peer.connection())
+}
+
+// doctorCheckTailscaleState reports whether Tailscale is running. A stopped
+// tailnet only fails when ssh_host is on it; a LAN or public endpoint works
+// without Tailscale, so there it is a warning.
+func doctorCheckTailscaleState(report *doctorReport, st tailscaleStatus, err error, sshHost string) bool {
+	add := report.fail
+	if !isTailnetAddress(sshHost) {
+		add = report.warn
+	}
+	switch {
+	case err != nil:
+		add("tailscale.state", "Tailscale стање", err.Error(), "покрени tailscaled (sudo systemctl enable --now tailscaled) па tailscale up")
+		return false
+	case !st.running():
+		add("tailscale.state", "Tailscale стање", st.BackendState, st.stateHint())
+		return false
+	}
+	report.ok("tailscale.state", "Tailscale стање", strings.TrimSpace(st.BackendState+" "+st.tailnetName()))
+	return true
+}
+
+// printTailscalePeerStatus is shown by init once the tailnet is up.
+func printTailscalePeerStatus(host string) {
+	if _, err := exec.LookPath("tailscale"); err != nil {
+		return
+	}
+	st, err := readTailscaleStatus()
+	if err != nil {
+		return
+	}
+	if peer, ok := st.findPeer(host); ok {
+		fmt.Printf("Tailscale: %s (%s) online, %s\n", peer.displayName(), peer.ipv4(), peer.connection())
+	}
+}
+
+// resolveTailscaleMachine finds the machine called name and returns the
+// address to put in ssh_host: its Tailscale IPv4, or the MagicDNS FQDN when
+// magicDNS is set.
+func resolveTailscaleMachine(st tailscaleStatus, name string, magicDNS bool) (string, *tailscalePeer, error) {
+	if !st.running() {
+		return "", nil, fmt.Errorf("tailnet није повезан (Tailscale стање: %s); %s", st.BackendState, st.stateHint())
+	}
+	peer, ok := st.findPeer(name)
+	if !ok {
+		return "", nil, fmt.Errorf("машина %q није у tailnet-у; имена су у tailscale status", name)
+	}
+	if magicDNS {
+		if peer.fqdn() == "" {
+			return "", nil, fmt.Errorf("%s нема MagicDNS име; укључи MagicDNS у Tailscale admin конзоли или изостави --magicdns", name)
+		}
+		return peer.fqdn(), peer, nil
+	}
+	if ip := peer.ipv4(); ip != "" {
+		return ip, peer, nil
+	}
+	return "", nil, fmt.Errorf("%s нема Tailscale IP адресу", name)
+}
+
+// serverURLWithHost keeps the scheme, port and path of serverURL and only
+// swaps the host.
+func serverURLWithHost(serverURL, host string) string {
+	u, err := url.Parse(strings.TrimSpace(serverURL))
+	if err != nil || u.Host == "" {
+		u, _ = url.Parse(defaultServerURL)
+	}
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	} else {
+		u.Host = host
+	}
+	return strings.TrimRight(u.String(), "/")
+}
+
+// applyTailscaleHost writes host into the managed Host block and into
+// ssh_host, server_url and tailscale_node in config.toml.
+func applyTailscaleHost(cfg appConfig, node, host, user string, port int) (string, string, error) {
+	sshPath, _, err := upsertSSHConfig(defaultHostAlias, host, user, port)
+	if err != nil {
+		return "", "", err
+	}
+	configPath, err := appConfigPath()
+	if err != nil {
+		return "", "", err
+	}
+	values := tailscaleConfigValues(cfg, node, host, user, port)
+	if err := updateAppConfigValues(configPath, values); err != nil {
+		return "", "", err
+	}
+	return sshPath, serverURLWithHost(cfg.ServerURL, host), nil
+}
+
+func tailscaleConfigValues(cfg appConfig, node, host, user string, port int) []configEntry {
+	return []configEntry{
+		{"server_url", serverURLWithHost(cfg.ServerURL, host)},
+		{"ssh_host", host},
+		{"ssh_port", port},
+		{"ssh_user", user},
+		{"tailscale_node", node},
+	}
+}
+
+// doctorCheckTailscaleNode compares ssh_host and server_url with the current
+// address of tailscale_node, which changes when the node is re-IP'd.
+func doctorCheckTailscaleNode(report *doctorReport, st tailscaleStatus, cfg appConfig) {
+	node := cfg.TailscaleNode
+	peer, ok := st.findPeer(node)
+	if !ok {
+		report.fail("tailscale.node", "tailscale_node", fmt.Sprintf("машина %q није у tailnet-у", node), "провери tailscale_node у config.toml (tailscale status)")
+		return
+	}
+
+	var stale []string
+	if p, ok := st.findPeer(cfg.SSHHost); !ok || p != peer {
+		stale = append(stale, "ssh_host "+cfg.SSHHost)
+	}
+	if u, err := url.Parse(resolveServerURL(cfg)); err == nil {
+		if p, ok := st.findPeer(u.Hostname()); !ok || p != peer {
+			stale = append(stale, "server_url "+u.Hostname())
+		}
+	}
+	if len(stale) == 0 {
+		report.ok("tailscale.node", "tailscale_node", fmt.Sprintf("%s -> %s", node, cfg.SSHHost))
+		return
+	}
+
+	magicDNS := net.ParseIP(cfg.SSHHost) == nil
+	want, _, err := resolveTailscaleMachine(st, node, magicDNS)
+	if err != nil {
+		report.fail("tailscale.node", "tailscale_node", fmt.Sprintf("%s не одговара машини %s: %v", strings.Join(stale, ", "), node, err), appName+" init --tailscale "+node)
+		return
+	}
+	message := fmt.Sprintf("%s не одговара машини %s (сада %s)", strings.Join(stale, ", "), node, want)
+	if cfg.Endpoint != "" {
+		report.fail("tailscale.node", "tailscale_node", message, fmt.Sprintf("упиши %s у [[endpoint]] %s у config.toml", want, cfg.Endpoint))
+		return
+	}
+	report.fail("tailscale.node", "tailscale_node", message, appName+" init --tailscale "+node)
+	report.remedy(fmt.Sprintf("упиши %s у Host %s и config.toml", want, defaultHostAlias), func(*doctorFixer) (string, error) {
+		_, serverURL, err := applyTailscaleHost(cfg, node, want, cfg.SSHUser, cfg.SSHPort)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ssh_host -> %s, server_url -> %s", want, serverURL), nil
+	})
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadTailscaleFixture(t *testing.T, name string) tailscaleStatus {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "tailscale", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := parseTailscaleStatus(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestParseTailscaleStatus(t *testing.T) {
+	st := loadTailscaleFixture(t, "running.json")
+	if !st.running() || st.tailnetName() != "crnobog69@github" || len(st.Peer) != 3 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+	if _, err := parseTailscaleStatus([]byte(`{"Version":"1.0"}`)); err == nil {
+		t.Fatal("status without BackendState should fail")
+	}
+	if _, err := parseTailscaleStatus([]byte(`not json`)); err == nil {
+		t.Fatal("invalid JSON should fail")
+	}
+}
+
+func TestTailscaleFindPeer(t *testing.T) {
+	st := loadTailscaleFixture(t, "running.json")
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"100.91.132.35", "gitea"},
+		{"fd7a:115c:a1e0::5b01:8423", "gitea"},
+		{"gitea.tail1234.ts.net", "gitea"},
+		{"gitea.tail1234.ts.net.", "gitea"},
+		{"GITEA", "gitea"},
+		{"Gitea Server", "gitea"},
+		{"laptop", "laptop"},
+		{"100.91.132.36", ""},
+		{"github.com", ""},
+	}
+	for _, tt := range tests {
+		peer, ok := st.findPeer(tt.host)
+		got := ""
+		if ok {
+			got = peer.displayName()
+		}
+		if got != tt.want {
+			t.Fatalf("findPeer(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestTailscalePeerConnection(t *testing.T) {
+	st := loadTailscaleFixture(t, "running.json")
+	tests := map[string]string{
+		"gitea":   "директно (192.168.1.20:41641)",
+		"nas":     "преко DERP relay-а (ams)",
+		"old-box": "нема активне везе (DERP fra)",
+	}
+	for host, want := range tests {
+		peer, ok := st.findPeer(host)
+		if !ok {
+			t.Fatalf("peer %s not found", host)
+		}
+		if got := peer.connection(); got != want {
+			t.Fatalf("%s connection = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestTailnetError(t *testing.T) {
+	running := loadTailscaleFixture(t, "running.json")
+	stopped := loadTailscaleFixture(t, "stopped.json")
+	needsLogin := loadTailscaleFixture(t, "needslogin.json")
+
+	tests := []struct {
+		name    string
+		st      tailscaleStatus
+		host    string
+		wantErr string
+	}{
+		{"online peer", running, "100.91.132.35", ""},
+		{"magicdns peer", running, "gitea.tail1234.ts.net", ""},
+		{"offline peer", running, "old-box", "offline"},
+		{"unknown tailnet ip", running, "100.99.99.99", "није у tailnet-у"},
+		{"not a tailnet host", running, "git.example.com", ""},
+		{"stopped", stopped, "100.91.132.35", "Tailscale стање: Stopped"},
+		{"stopped lan host", stopped, "192.168.1.20", ""},
+		{"needs login", needsLogin, "gitea.tail1234.ts.net", "https://login.tailscale.com/a/1a2b3c4d5e6f"},
+	}
+	for _, tt := range tests {
+		err := tailnetError(tt.st, tt.host)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsTailnetAddress(t *testing.T) {
+	for host, want := range map[string]bool{
+		"100.91.132.35":          true,
+		"100.128.0.1":            false,
+		"192.168.1.20":           false,
+		"fd7a:115c:a1e0::1":      true,
+		"gitea.tail1234.ts.net":  true,
+		"gitea.tail1234.ts.net.": true,
+		"git.example.com":        false,
+	} {
+		if got := isTailnetAddress(host); got != want {
+			t.Fatalf("isTailnetAddress(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestRepoHost(t *testing.T) {
+	cfg := appConfig{SSHHost: "100.91.132.35", ServerURL: "http://gitea.tail1234.ts.net:5000", Protocol: "ssh"}
+	if got := repoHost(cfg, false); got != "100.91.132.35" {
+		t.Fatalf("ssh host = %q", got)
+	}
+	if got := repoHost(cfg, true); got != "gitea.tail1234.ts.net" {
+		t.Fatalf("https host = %q", got)
+	}
+}
+
+func TestResolveTailscaleMachine(t *testing.T) {
+	st := loadTailscaleFixture(t, "running.json")
+	if host, _, err := resolveTailscaleMachine(st, "gitea", false); err != nil || host != "100.91.132.35" {
+		t.Fatalf("ip: %q, %v", host, err)
+	}
+	if host, _, err := resolveTailscaleMachine(st, "gitea", true); err != nil || host != "gitea.tail1234.ts.net" {
+		t.Fatalf("magicdns: %q, %v", host, err)
+	}
+	if _, _, err := resolveTailscaleMachine(st, "missing", false); err == nil {
+		t.Fatal("unknown machine should fail")
+	}
+	if _, _, err := resolveTailscaleMachine(loadTailscaleFixture(t, "stopped.json"), "gitea", false); err == nil || !strings.Contains(err.Error(), "tailscale up") {
+		t.Fatalf("stopped tailnet: %v", err)
+	}
+}
+
+func TestServerURLWithHost(t *testing.T) {
+	tests := []struct {
+		serverURL, host, want string
+	}{
+		{"http://100.91.132.35:5000", "100.91.140.2", "http://100.91.140.2:5000"},
+		{"https://gitea.tail1234.ts.net/", "100.91.140.2", "https://100.91.140.2"},
+		{"http://100.91.132.35:5000/gitea", "gitea.tail1234.ts.net", "http://gitea.tail1234.ts.net:5000/gitea"},
+		{"http://100.91.132.35:5000", "fd7a:115c:a1e0::1", "http://[fd7a:115c:a1e0::1]:5000"},
+		{"", "100.91.140.2", "http://100.91.140.2:5000"},
+	}
+	for _, tt := range tests {
+		if got := serverURLWithHost(tt.serverURL, tt.host); got != tt.want {
+			t.Fatalf("serverURLWithHost(%q, %q) = %q, want %q", tt.serverURL, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestDoctorCheckTailscaleNode(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	st := loadTailscaleFixture(t, "running.json")
+
+	cfg := appConfig{ServerURL: "http://100.91.132.35:5000", SSHHost: "gitea.tail1234.ts.net", SSHPort: 222, SSHUser: "git", TailscaleNode: "gitea"}
+	r := &doctorReport{}
+	doctorCheckTailscaleNode(r, st, cfg)
+	if len(r.Results) != 1 || r.Results[0].Severity != severityOK {
+		t.Fatalf("matching node should pass: %+v", r.Results)
+	}
+
+	// The node was re-IP'd: ssh_host still points at the old address.
+	cfg.SSHHost = "100.91.1.1"
+	cfg.ServerURL = "http://100.91.1.1:5000"
+	r = &doctorReport{}
+	doctorCheckTailscaleNode(r, st, cfg)
+	if len(r.Results) != 1 || r.Results[0].Severity != severityFail || !r.Results[0].Fixable {
+		t.Fatalf("stale address should be a fixable failure: %+v", r.Results)
+	}
+	if !strings.Contains(r.Results[0].Message, "сада 100.91.132.35") {
+		t.Fatalf("message should name the current address: %s", r.Results[0].Message)
+	}
+	if _, err := applyDoctorFixes(r, &doctorFixer{yes: true, out: io.Discard}); err != nil {
+		t.Fatal(err)
+	}
+
+	fixed, err := loadAppConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixed.SSHHost != "100.91.132.35" || fixed.ServerURL != "http://100.91.132.35:5000" || fixed.TailscaleNode != "gitea" {
+		t.Fatalf("config not updated: %+v", fixed)
+	}
+	sshConfig, _ := os.ReadFile(filepath.Join(home, ".ssh", "config"))
+	if !strings.Contains(string(sshConfig), "HostName 100.91.132.35") {
+		t.Fatalf("Host block not updated:\n%s", sshConfig)
+	}
+
+	cfg.TailscaleNode = "missing"
+	r = &doctorReport{}
+	doctorCheckTailscaleNode(r, st, cfg)
+	if len(r.Results) != 1 || r.Results[0].Severity != severityFail {
+		t.Fatalf("unknown node should fail: %+v", r.Results)
+	}
+}
+
+func TestDoctorCheckTailscaleState(t *testing.T) {
+	running := loadTailscaleFixture(t, "running.json")
+	stopped := loadTailscaleFixture(t, "stopped.json")
+
+	tests := []struct {
+		name     string
+		st       tailscaleStatus
+		err      error
+		host     string
+		want     string
+		wantNext bool
+	}{
+		{"running", running, nil, "192.168.1.20", severityOK, true},
+		{"stopped lan host", stopped, nil, "192.168.1.20", severityWarn, false},
+		{"stopped tailnet host", stopped, nil, "gitea.tail1234.ts.net", severityFail, false},
+		{"missing lan host", tailscaleStatus{}, errors.New("tailscale није инсталиран"), "git.example.com", severityWarn, false},
+		{"missing tailnet host", tailscaleStatus{}, errors.New("tailscale није инсталиран"), "100.91.132.35", severityFail, false},
+	}
+	for _, tt := range tests {
+		r := &doctorReport{}
+		next := doctorCheckTailscaleState(r, tt.st, tt.err, tt.host)
+		if next != tt.wantNext || len(r.Results) != 1 || r.Results[0].Severity != tt.want {
+			t.Fatalf("%s: got %v %+v, want %s", tt.name, next, r.Results, tt.want)
+		}
+	}
+}
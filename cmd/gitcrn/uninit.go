@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// uninitPlan is everything uninit would remove, collected up front so it can
+// be shown before anything changes.
+type uninitPlan struct {
+	SSH         sshConfigChange
+	ConfigPath  string
+	KnownHosts  string
+	HostEntries []string
+	ServerURL   string
+	Token       string
+}
+
+func runUninit(args []string) error {
+	fs := newFlagSet("uninit")
+
+	removeConfig := fs.Bool("config", false, "Обриши и config.toml")
+	removeKnownHosts := fs.Bool("known-hosts", false, "Уклони сервер из ~/.ssh/known_hosts")
+	revoke := fs.Bool("revoke-token", false, "Опозови token на серверу")
+	all := fs.Bool("all", false, "Исто што и --config --known-hosts --revoke-token")
+	yes := fs.Bool("yes", false, "Без питања за потврду")
+	dryRun := fs.Bool("dry-run", false, "Само прикажи шта би било уклоњено")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printUninitUsage(os.Stdout)
+			return nil
+		}
+		printUninitUsage(os.Stderr)
+		return err
+	}
+	if fs.NArg() != 0 {
+		printUninitUsage(os.Stderr)
+		return fmt.Errorf("неочекивани аргументи: %s", strings.Join(fs.Args(), " "))
+	}
+	// Only an explicit --revoke-token requires a token; --all revokes one
+	// if it is configured.
+	tokenRequired := *revoke
+	if *all {
+		*removeConfig, *removeKnownHosts, *revoke = true, true, true
+	}
+
+	plan, err := planUninit(*removeConfig, *removeKnownHosts, *revoke, tokenRequired)
+	if err != nil {
+		return err
+	}
+	if plan.empty() {
+		fmt.Println("Нема ничега за уклањање.")
+		return nil
+	}
+	printUninitPlan(os.Stdout, plan)
+	if *dryRun {
+		return nil
+	}
+
+	if !*yes {
+		if !isTerminal(os.Stdin) {
+			return errors.New("uninit без терминала захтева --yes")
+		}
+		ok, err := promptYesNo(os.Stderr, stdin, "Наставити? [y/N]: ")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("прекинуто")
+		}
+	}
+	return applyUninit(os.Stdout, plan, readPassword)
+}
+
+func (p uninitPlan) empty() bool {
+	return !p.SSH.changed() && p.ConfigPath == "" && len(p.HostEntries) == 0 && p.Token == ""
+}
+
+func planUninit(removeConfig, removeKnownHosts, revoke, tokenRequired bool) (uninitPlan, error) {
+	var plan uninitPlan
+
+	ssh, err := planSSHConfigRemoval(defaultHostAlias)
+	if err != nil {
+		return plan, err
+	}
+	plan.SSH = ssh
+
+	cfg, err := loadAppConfig()
+	if err != nil {
+		return plan, err
+	}
+
+	if removeConfig {
+		path, err := appConfigPath()
+		if err != nil {
+			return plan, err
+		}
+		if fileExists(path) {
+			plan.ConfigPath = path
+		}
+	}
+
+	if removeKnownHosts {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return plan, fmt.Errorf("детекција home директоријума: %w", err)
+		}
+		plan.KnownHosts = filepath.Join(home, ".ssh", "known_hosts")
+		plan.HostEntries = knownHostsEntries(plan.KnownHosts, uninitHostNames(cfg, ssh))
+	}
+
+	if revoke {
+		plan.Token = resolveToken(cfg)
+		if plan.Token == "" && tokenRequired {
+			return plan, errMissingToken
+		}
+		if plan.Token != "" {
+			plan.ServerURL = resolveServerURL(cfg)
+		}
+	}
+	return plan, nil
+}
+
+// planSSHConfigRemoval drops the gitcrn Host block from ~/.ssh/config. The
+// rest of the file is kept byte for byte, line endings included.
+func planSSHConfigRemoval(alias string) (sshConfigChange, error) {
+	configPath, err := sshConfigPath()
+	if err != nil {
+		return sshConfigChange{}, err
+	}
+
+	change := sshConfigChange{Path: configPath}
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return change, nil
+	}
+	if err != nil {
+		return sshConfigChange{}, fmt.Errorf("читање %s: %w", configPath, err)
+	}
+	change.Exists = true
+	change.Old = string(data)
+	change.New = removeSSHHostBlock(change.Old, alias)
+	return change, nil
+}
+
+// removeSSHHostBlock removes the block between the gitcrn markers and any
+// older unmarked "Host <alias>" block, with one blank line that separated it
+// from the previous block. A Host line that also names other hosts is left
+// alone, since it is not only gitcrn's.
+func removeSSHHostBlock(content, alias string) string {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var out []string
+	removed := false
+	for i := 0; i < len(lines); {
+		curr := strings.TrimSpace(lines[i])
+
+		end := -1
+		if curr == sshBlockBegin {
+			for j := i + 1; j < len(lines); j++ {
+				if strings.TrimSpace(lines[j]) == sshBlockEnd {
+					end = j + 1
+					break
+				}
+			}
+		} else if patterns, ok := parseHostLine(curr); ok && len(patterns) == 1 && strings.EqualFold(patterns[0], alias) {
+			end = i + 1
+			for end < len(lines) && !isSSHBlockStart(lines[end]) {
+				end++
+			}
+			for end > i+1 && isBlankOrComment(lines[end-1]) {
+				end--
+			}
+		}
+		if end < 0 {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+
+		// init put a blank line before the block; take it away again unless
+		// the block was followed by another one that still needs it.
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" &&
+			(end == len(lines) || strings.TrimSpace(lines[end]) == "") {
+			out = out[:len(out)-1]
+		}
+		// Likewise the blank line after a block at the top of the file.
+		if len(out) == 0 && end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+			end++
+		}
+		removed = true
+		i = end
+	}
+	if !removed {
+		return content
+	}
+	result := strings.Join(out, "")
+	if strings.TrimSpace(result) == "" {
+		return ""
+	}
+	return result
+}
+
+// uninitHostNames lists the servers ssh may have recorded in known_hosts:
+// the configured address, every endpoint and whatever HostName the ssh
+// config pointed at.
+func uninitHostNames(cfg appConfig, ssh sshConfigChange) []string {
+	var names []string
+	add := func(host string, port int) {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			return
+		}
+		name := knownHostsName(host, port)
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	add(cfg.SSHHost, cfg.SSHPort)
+	for _, e := range cfg.Endpoints {
+		add(e.SSHHost, e.SSHPort)
+	}
+	if ssh.Exists {
+		if resolved, err := resolveSSHHost(defaultHostAlias, ssh.Path); err == nil && resolved.get("hostname") != "" {
+			port, _ := strconv.Atoi(resolved.get("port"))
+			add(resolved.get("hostname"), port)
+		}
+	}
+	return names
+}
+
+// knownHostsName is how ssh writes a host in known_hosts: bare for port 22,
+// [host]:port otherwise.
+func knownHostsName(host string, port int) string {
+	if port == 0 || port == 22 {
+		return host
+	}
+	return fmt.Sprintf("[%s]:%d", host, port)
+}
+
+// knownHostsEntries returns the names that have an entry in path. ssh-keygen
+// does the lookup because entries are usually hashed.
+func knownHostsEntries(path string, names []string) []string {
+	if !fileExists(path) {
+		return nil
+	}
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return nil
+	}
+	var found []string
+	for _, name := range names {
+		if exec.Command("ssh-keygen", "-F", name, "-f", path).Run() == nil {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+func printUninitPlan(w io.Writer, plan uninitPlan) {
+	fmt.Fprintln(w, appName+" uninit ће:")
+	if plan.SSH.changed() {
+		fmt.Fprintf(w, "  - уклонити Host %s блок из %s (уз резервну копију)\n", defaultHostAlias, plan.SSH.Path)
+	}
+	if len(plan.HostEntries) > 0 {
+		fmt.Fprintf(w, "  - уклонити из %s: %s\n", plan.KnownHosts, strings.Join(plan.HostEntries, ", "))
+	}
+	if plan.Token != "" {
+		fmt.Fprintf(w, "  - опозвати token ...%s на %s\n", tokenSuffix(plan.Token), plan.ServerURL)
+	}
+	if plan.ConfigPath != "" {
+		fmt.Fprintf(w, "  - обрисати %s\n", plan.ConfigPath)
+	}
+	fmt.Fprintln(w, "Остаје: SSH кључеви ("+appName+" их не прави) и git remote-и у репоима.")
+
+	if plan.SSH.changed() {
+		fmt.Fprintln(w)
+		printFileDiff(w, plan.SSH.Path, plan.SSH.Path+" ("+appName+" uninit)", plan.SSH.Old, plan.SSH.New)
+	}
+}
+
+// applyUninit revokes the token first: if that fails nothing else is touched,
+// so the token is still in config.toml for another try.
+func applyUninit(w io.Writer, plan uninitPlan, askPassword func(login string) (string, error)) error {
+	if plan.Token != "" {
+		name, err := revokeGiteaToken(plan.ServerURL, plan.Token, askPassword)
+		if err != nil {
+			return fmt.Errorf("опозивање token-а: %w", err)
+		}
+		fmt.Fprintln(w, colorize("Token опозван: "+name, ansiGreen, stdoutColor))
+	}
+
+	if plan.SSH.changed() {
+		backup, err := plan.SSH.apply()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, colorize("Host "+defaultHostAlias+" уклоњен из "+plan.SSH.Path, ansiGreen, stdoutColor))
+		if backup != "" {
+			fmt.Fprintln(w, "Резервна копија: "+backup)
+		}
+	}
+
+	for _, name := range plan.HostEntries {
+		// ssh-keygen -R keeps the previous file as known_hosts.old.
+		out, err := exec.Command("ssh-keygen", "-R", name, "-f", plan.KnownHosts).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("ssh-keygen -R %s: %s", name, firstOutputLine(strings.TrimSpace(string(out))))
+		}
+		fmt.Fprintln(w, colorize("Уклоњен из known_hosts: "+name, ansiGreen, stdoutColor))
+	}
+
+	if plan.ConfigPath != "" {
+		if err := os.Remove(plan.ConfigPath); err != nil {
+			return err
+		}
+		// The directory only held config.toml; leave it if anything else is there.
+		_ = os.Remove(filepath.Dir(plan.ConfigPath))
+		fmt.Fprintln(w, colorize("Обрисан "+plan.ConfigPath, ansiGreen, stdoutColor))
+	}
+	return nil
+}
+
+type giteaAccessToken struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	TokenLastEight string `json:"token_last_eight"`
+}
+
+// revokeGiteaToken deletes token on the server and returns its name. Gitea
+// manages tokens only with basic auth; the token itself is tried as the
+// password first and askPassword is used only if the server refuses it.
+func revokeGiteaToken(serverURL, token string, askPassword func(login string) (string, error)) (string, error) {
+	login, err := giteaCurrentUser(serverURL, token)
+	if err != nil {
+		return "", err
+	}
+	tokensPath := "users/" + url.PathEscape(login) + "/tokens"
+
+	password := token
+	var tokens []giteaAccessToken
+	err = giteaBasicJSON(http.MethodGet, serverURL, tokensPath, login, password, &tokens)
+	var apiErr *giteaAPIError
+	if errors.As(err, &apiErr) && (apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden) {
+		if password, err = askPassword(login); err != nil {
+			return "", err
+		}
+		err = giteaBasicJSON(http.MethodGet, serverURL, tokensPath, login, password, &tokens)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, t := range tokens {
+		if t.TokenLastEight != "" && t.TokenLastEight == tokenSuffix(token) {
+			path := tokensPath + "/" + strconv.FormatInt(t.ID, 10)
+			if err := giteaBasicJSON(http.MethodDelete, serverURL, path, login, password, nil); err != nil {
+				return "", err
+			}
+			return t.Name, nil
+		}
+	}
+	return "", fmt.Errorf("token ...%s није међу token-има корисника %s", tokenSuffix(token), login)
+}
+
+func giteaBasicJSON(method, serverURL, apiPath, login, password string, out any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, serverURL+"/api/v1/"+apiPath, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(login, password)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", appName)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &giteaAPIError{Status: resp.StatusCode, Message: giteaErrorMessage(resp.Body)}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func tokenSuffix(token string) string {
+	if len(token) <= 8 {
+		return token
+	}
+	return token[len(token)-8:]
+}
+
+// readPassword asks for the Gitea password without echoing it. There is no
+// terminal API in the standard library, so echo is turned off with stty;
+// on Windows the input stays visible.
+func readPassword(login string) (string, error) {
+	if !isTerminal(os.Stdin) {
+		return "", errors.New("лозинка се уноси само из терминала")
+	}
+	fmt.Fprintf(os.Stderr, "Gitea лозинка за %s: ", login)
+	if runtime.GOOS != "windows" {
+		stty := func(arg string) {
+			cmd := exec.Command("stty", arg)
+			cmd.Stdin = os.Stdin
+			_ = cmd.Run()
+		}
+		stty("-echo")
+		defer func() {
+			stty("echo")
+			fmt.Fprintln(os.Stderr)
+		}()
+	}
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("лозинка није унета")
+	}
+	return password, nil
+}
+
+func printUninitUsage(w io.Writer) {
+	fmt.Fprintf(w, `Коришћење:
+  %s uninit [--config] [--known-hosts] [--revoke-token] [--all] [--yes] [--dry-run]
+
+Уклања Host %s блок из ~/.ssh/config; остали host-ови остају бајт по бајт
+исти, а пре измене се прави резервна копија. Прво испише шта ће бити
+уклоњено (и diff) и пита за потврду; без терминала тражи --yes.
+--config брише и config.toml, --known-hosts уклања ssh_host и endpoint-е из
+~/.ssh/known_hosts, а --revoke-token опозива token на серверу (Gitea то
+дозвољава само уз basic auth, па може да пита за лозинку). --all је све то;
+без token-а прескаче опозив.
+--dry-run само испише план. SSH кључеве %s не прави, па их ни не брише.
+`, appName, defaultHostAlias, appName)
+}
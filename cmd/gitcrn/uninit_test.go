@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoveSSHHostBlock(t *testing.T) {
+	before := "Host work\r\n    User me\r\n\r\n# work laptop\r\nHost laptop\r\n    HostName 10.0.0.2\r\n"
+	after := "\r\nHost *\r\n    ServerAliveInterval 30\r\n"
+	content := before + "\n" + wrapSSHHostBlock(renderSSHHostBlock("gitcrn", "100.91.132.35", "git", 222), []string{"    IdentityFile ~/.ssh/git"}) + "\n" + after
+
+	got := removeSSHHostBlock(content, "gitcrn")
+	if got != before+after {
+		t.Fatalf("other hosts must stay byte for byte:\n%q", got)
+	}
+
+	legacy := "Host gitcrn\n    HostName 1.2.3.4\n\n# next\nHost other\n    User x\n"
+	if got := removeSSHHostBlock(legacy, "gitcrn"); got != "# next\nHost other\n    User x\n" {
+		t.Fatalf("unexpected legacy removal:\n%q", got)
+	}
+
+	shared := "Host gitcrn backup\n    HostName 1.2.3.4\n"
+	if got := removeSSHHostBlock(shared, "gitcrn"); got != shared {
+		t.Fatalf("a Host line naming other hosts must stay:\n%q", got)
+	}
+
+	only := wrapSSHHostBlock(renderSSHHostBlock("gitcrn", "100.91.132.35", "git", 222), nil) + "\n"
+	if got := removeSSHHostBlock(only, "gitcrn"); got != "" {
+		t.Fatalf("expected empty file, got %q", got)
+	}
+}
+
+func TestRemoveSSHHostBlockAfterInit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	original := "Host work\n    User me\n"
+	path := filepath.Join(home, ".ssh", "config")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := upsertSSHConfig("gitcrn", "100.91.132.35", "git", 222); err != nil {
+		t.Fatal(err)
+	}
+
+	change, err := planSSHConfigRemoval("gitcrn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !change.changed() || change.New != original {
+		t.Fatalf("uninit should undo init exactly:\n%q", change.New)
+	}
+}
+
+func TestKnownHostsName(t *testing.T) {
+	if got := knownHostsName("100.91.132.35", 222); got != "[100.91.132.35]:222" {
+		t.Fatalf("unexpected name %q", got)
+	}
+	if got := knownHostsName("git.example.com", 22); got != "git.example.com" {
+		t.Fatalf("unexpected name %q", got)
+	}
+}
+
+func TestRevokeGiteaToken(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef89abcdef"
+	var deleted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/user":
+			w.Write([]byte(`{"login":"vltc"}`))
+		case r.URL.Path == "/api/v1/users/vltc/tokens" && r.Method == http.MethodGet:
+			// Like Gitea, refuse the token as a basic auth password.
+			if login, pass, ok := r.BasicAuth(); !ok || login != "vltc" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"message":"auth required"}`))
+				return
+			}
+			w.Write([]byte(`[{"id":3,"name":"laptop","token_last_eight":"00000000"},{"id":7,"name":"gitcrn","token_last_eight":"89abcdef"}]`))
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	asked := 0
+	name, err := revokeGiteaToken(srv.URL, token, func(login string) (string, error) {
+		asked++
+		return "secret", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "gitcrn" || deleted != "/api/v1/users/vltc/tokens/7" || asked != 1 {
+		t.Fatalf("unexpected revoke: name=%q deleted=%q asked=%d", name, deleted, asked)
+	}
+
+	_, err = revokeGiteaToken(srv.URL, token, func(string) (string, error) {
+		return "", errors.New("нема лозинке")
+	})
+	if err == nil || err.Error() != "нема лозинке" {
+		t.Fatalf("expected password error, got %v", err)
+	}
+}
+
+func TestPlanUninitAllWithoutToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GITCRN_TOKEN", "")
+	t.Setenv("GITEA_TOKEN", "")
+
+	plan, err := planUninit(true, false, true, false)
+	if err != nil {
+		t.Fatalf("--all must work without a token: %v", err)
+	}
+	if plan.Token != "" || plan.ServerURL != "" {
+		t.Fatalf("nothing to revoke expected: %+v", plan)
+	}
+	if _, err := planUninit(false, false, true, true); !errors.Is(err, errMissingToken) {
+		t.Fatalf("--revoke-token without a token must fail, got %v", err)
+	}
+}
+
+func TestApplyUninitKeepsFilesWhenRevokeFails(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if _, _, err := upsertSSHConfig("gitcrn", "100.91.132.35", "git", 222); err != nil {
+		t.Fatal(err)
+	}
+	change, err := planSSHConfigRemoval("gitcrn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	plan := uninitPlan{SSH: change, ServerURL: srv.URL, Token: "revoked-token"}
+	if err := applyUninit(io.Discard, plan, nil); err == nil {
+		t.Fatal("expected revoke error")
+	}
+	data, _ := os.ReadFile(change.Path)
+	if !strings.Contains(string(data), "# BEGIN gitcrn") {
+		t.Fatal("ssh config must stay when the token could not be revoked")
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	defaultUpdateCheckInterval = 24 * time.Hour
+	updateCheckTimeout         = 10 * time.Second
+
+	// updateCheckCommand is the hidden subcommand that refreshes the cache
+	// in a detached child process.
+	updateCheckCommand = "__update-check"
+
+	updateChannelStable     = "stable"
+	updateChannelPrerelease = "prerelease"
+)
+
+type releaseInfo struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+type updateCheckCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Source    string    `json:"source,omitempty"`
+	Channel   string    `json:"channel,omitempty"`
+	Latest    string    `json:"latest,omitempty"`
+}
+
+func updateCheckCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("детекција cache директоријума: %w", err)
+	}
+	return filepath.Join(dir, appName, "update-check.json"), nil
+}
+
+func readUpdateCheckCache(path string) (updateCheckCache, error) {
+	var cache updateCheckCache
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, nil
+		}
+		return cache, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		// A corrupt cache is treated as empty so the next check rewrites it.
+		return updateCheckCache{}, nil
+	}
+	return cache, nil
+}
+
+func writeUpdateCheckCache(path string, cache updateCheckCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func updateCheckDue(cache updateCheckCache, now time.Time, interval time.Duration) bool {
+	if cache.CheckedAt.IsZero() || cache.CheckedAt.After(now) {
+		return true
+	}
+	return now.Sub(cache.CheckedAt) >= interval
+}
+
+// pendingUpdate returns the cached tag when it is newer than current.
+func pendingUpdate(cache updateCheckCache, current string) (string, bool) {
+	if cache.Latest == "" {
+		return "", false
+	}
+	cmp, ok := compareSemver(cache.Latest, current)
+	if !ok || cmp <= 0 {
+		return "", false
+	}
+	return cache.Latest, true
+}
+
+func resolveUpdateCheckInterval(cfg appConfig) time.Duration {
+	if cfg.UpdateCheckInterval > 0 {
+		return cfg.UpdateCheckInterval
+	}
+	return defaultUpdateCheckInterval
+}
+
+// maybePrintUpdateNotice prints a notice from the cached result and, when the
+// cache is stale, starts a background refresh for the next run. It never
+// touches the network itself.
+func maybePrintUpdateNotice() {
+	if !isTerminal(os.Stdout) {
+		return
+	}
+	path, err := updateCheckCachePath()
+	if err != nil {
+		return
+	}
+	cache, err := readUpdateCheckCache(path)
+	if err != nil {
+		return
+	}
+
+	cfg, _ := loadAppConfig()
+	source := resolveUpdateSource(cfg).cacheKey()
+	channel := resolveUpdateChannel(cfg)
+	if cache.Source != source || cache.Channel != channel {
+		// The cached tag came from another source or channel; wait for a
+		// fresh one.
+		cache = updateCheckCache{}
+	}
+
+	if latestTag, ok := pendingUpdate(cache, version); ok {
+		printUpdateNotice(latestTag)
+	}
+
+	if !updateCheckDue(cache, time.Now(), resolveUpdateCheckInterval(cfg)) {
+		return
+	}
+
+	// Record the attempt first so an offline machine does not spawn a
+	// refresh on every invocation.
+	cache.CheckedAt = time.Now()
+	cache.Source = source
+	cache.Channel = channel
+	if err := writeUpdateCheckCache(path, cache); err != nil {
+		return
+	}
+	startBackgroundUpdateCheck()
+}
+
+func printUpdateNotice(latestTag string) {
+	fmt.Println(colorize(fmt.Sprintf("Доступна је нова верзија: %s (тренутна %s)", latestTag, version), ansiYellow, stdoutColor))
+	fmt.Printf("Ажурирај овом командом:\n  %s self-update\n", appName)
+	fmt.Println("или скриптом:")
+	if runtime.GOOS == "windows" {
+		fmt.Printf("  %s\n", updateWinCmd)
+		return
+	}
+	fmt.Printf("  %s\n", updateLinuxCmd)
+}
+
+func startBackgroundUpdateCheck() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	cmd := exec.Command(exe, updateCheckCommand)
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	_ = cmd.Process.Release()
+}
+
+// runUpdateCheckRefresh fetches the latest tag and stores it in the cache.
+// Failures are silent; the attempt time was already recorded by the parent.
+func runUpdateCheckRefresh() {
+	path, err := updateCheckCachePath()
+	if err != nil {
+		return
+	}
+	cfg, _ := loadAppConfig()
+	src := resolveUpdateSource(cfg)
+	channel := resolveUpdateChannel(cfg)
+	latestTag, err := fetchLatestReleaseTag(src, channel, updateCheckTimeout)
+	if err != nil {
+		return
+	}
+	_ = writeUpdateCheckCache(path, updateCheckCache{
+		CheckedAt: time.Now(),
+		Source:    src.cacheKey(),
+		Channel:   channel,
+		Latest:    latestTag,
+	})
+}
+
+func resolveUpdateChannel(cfg appConfig) string {
+	if cfg.UpdateChannel == updateChannelPrerelease {
+		return updateChannelPrerelease
+	}
+	return updateChannelStable
+}
+
+// pickLatestRelease returns the highest version among published releases.
+// The stable channel ignores releases marked as prerelease and tags with a
+// prerelease suffix.
+func pickLatestRelease(releases []releaseInfo, channel string) (string, bool) {
+	var (
+		best    semver
+		bestTag string
+	)
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		tag := strings.TrimSpace(r.TagName)
+		v, ok := parseSemver(tag)
+		if !ok {
+			continue
+		}
+		if channel != updateChannelPrerelease && (r.Prerelease || v.isPrerelease()) {
+			continue
+		}
+		if bestTag == "" || v.compare(best) > 0 {
+			best, bestTag = v, tag
+		}
+	}
+	return bestTag, bestTag != ""
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUpdateCheckCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gitcrn", "update-check.json")
+
+	cache, err := readUpdateCheckCache(path)
+	if err != nil || !cache.CheckedAt.IsZero() {
+		t.Fatalf("missing cache should be empty, got %+v, %v", cache, err)
+	}
+
+	want := updateCheckCache{CheckedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Latest: "v1.2.3"}
+	if err := writeUpdateCheckCache(path, want); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := readUpdateCheckCache(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !got.CheckedAt.Equal(want.CheckedAt) || got.Latest != want.Latest {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readUpdateCheckCache(path); err != nil || got.Latest != "" {
+		t.Fatalf("corrupt cache should read as empty, got %+v, %v", got, err)
+	}
+}
+
+func TestUpdateCheckDue(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name      string
+		checkedAt time.Time
+		want      bool
+	}{
+		{"never", time.Time{}, true},
+		{"recent", now.Add(-time.Hour), false},
+		{"stale", now.Add(-25 * time.Hour), true},
+		{"future clock skew", now.Add(time.Hour), true},
+	}
+	for _, tc := range cases {
+		got := updateCheckDue(updateCheckCache{CheckedAt: tc.checkedAt}, now, 24*time.Hour)
+		if got != tc.want {
+			t.Fatalf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPendingUpdate(t *testing.T) {
+	if tag, ok := pendingUpdate(updateCheckCache{Latest: "v1.3.0"}, "v1.2.0"); !ok || tag != "v1.3.0" {
+		t.Fatalf("expected pending v1.3.0, got %q %v", tag, ok)
+	}
+	if _, ok := pendingUpdate(updateCheckCache{Latest: "v1.2.0"}, "v1.2.0"); ok {
+		t.Fatalf("same version must not be pending")
+	}
+	if _, ok := pendingUpdate(updateCheckCache{Latest: "v1.3.0"}, "dev"); ok {
+		t.Fatalf("dev build must not be pending")
+	}
+	if _, ok := pendingUpdate(updateCheckCache{}, "v1.0.0"); ok {
+		t.Fatalf("empty cache must not be pending")
+	}
+}
+
+func TestPickLatestRelease(t *testing.T) {
+	releases := []releaseInfo{
+		{TagName: "v1.2.0"},
+		{TagName: "v1.3.0-rc.2", Prerelease: true},
+		{TagName: "v1.3.0-rc.10", Prerelease: true},
+		{TagName: "v1.4.0", Draft: true},
+		{TagName: "v1.2.1-beta"},
+		{TagName: "nightly"},
+		{TagName: "v1.1.9"},
+	}
+
+	if tag, ok := pickLatestRelease(releases, updateChannelStable); !ok || tag != "v1.2.0" {
+		t.Fatalf("stable: got %q %v", tag, ok)
+	}
+	if tag, ok := pickLatestRelease(releases, updateChannelPrerelease); !ok || tag != "v1.3.0-rc.10" {
+		t.Fatalf("prerelease: got %q %v", tag, ok)
+	}
+	if _, ok := pickLatestRelease([]releaseInfo{{TagName: "v2.0.0-rc.1", Prerelease: true}}, updateChannelStable); ok {
+		t.Fatalf("stable channel must ignore prereleases")
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	updateProviderGitHub = "github"
+	updateProviderGitea  = "gitea"
+
+	defaultUpdateOwner = "crnobog69"
+	defaultUpdateRepo  = "gitcrn-cli-bin"
+	githubAPIURL       = "https://api.github.com"
+	githubURL          = "https://github.com"
+)
+
+// updateSource describes where releases of gitcrn itself are published. It
+// mirrors the --provider/--owner/--repo options of scripts/install.sh.
+type updateSource struct {
+	Provider    string
+	ReleasesURL string
+	DownloadURL string
+	Token       string
+}
+
+func resolveUpdateSource(cfg appConfig) updateSource {
+	owner := fallback(strings.TrimSpace(cfg.UpdateOwner), defaultUpdateOwner)
+	repo := fallback(strings.TrimSpace(cfg.UpdateRepo), defaultUpdateRepo)
+	repoPart := url.PathEscape(owner) + "/" + url.PathEscape(repo)
+
+	var src updateSource
+	if cfg.UpdateProvider == updateProviderGitea {
+		server := resolveServerURL(cfg)
+		src = updateSource{
+			Provider:    updateProviderGitea,
+			ReleasesURL: server + "/api/v1/repos/" + repoPart + "/releases",
+			DownloadURL: server + "/" + repoPart + "/releases/download",
+			Token:       resolveToken(cfg),
+		}
+	} else {
+		src = updateSource{
+			Provider:    updateProviderGitHub,
+			ReleasesURL: githubAPIURL + "/repos/" + repoPart + "/releases",
+			DownloadURL: githubURL + "/" + repoPart + "/releases/download",
+		}
+	}
+
+	override := strings.TrimSpace(os.Getenv("GITCRN_UPDATE_BASE_URL"))
+	if override == "" {
+		override = strings.TrimSpace(cfg.UpdateBaseURL)
+	}
+	if override != "" {
+		src.DownloadURL = strings.TrimRight(override, "/")
+	}
+	return src
+}
+
+// authorize adds the Gitea token, but only for requests to the Gitea server
+// itself so it never leaks to an overridden download host.
+func (s updateSource) authorize(req *http.Request) {
+	if s.Token == "" || s.Provider != updateProviderGitea {
+		return
+	}
+	base, err := url.Parse(s.ReleasesURL)
+	if err != nil || req.URL.Scheme != base.Scheme || req.URL.Host != base.Host {
+		return
+	}
+	req.Header.Set("Authorization", "token "+s.Token)
+}
+
+// cacheKey identifies the source in the update check cache, so switching
+// provider or repo discards a tag read from elsewhere.
+func (s updateSource) cacheKey() string {
+	return s.ReleasesURL
+}
+
+// fetchLatestReleaseTag lists releases instead of asking for /releases/latest,
+// so the prerelease channel can see release candidates and the stable channel
+// is decided by version precedence rather than publish date.
+func fetchLatestReleaseTag(src updateSource, channel string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	query := "?per_page=50"
+	if src.Provider == updateProviderGitea {
+		query = "?limit=50"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.ReleasesURL+query, nil)
+	if err != nil {
+		return "", err
+	}
+	if src.Provider == updateProviderGitHub {
+		req.Header.Set("Accept", "application/vnd.github+json")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+	req.Header.Set("User-Agent", appName)
+	src.authorize(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("status %d (%s)", resp.StatusCode, src.ReleasesURL)
+	}
+
+	var releases []releaseInfo
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return "", err
+	}
+	tag, ok := pickLatestRelease(releases, channel)
+	if !ok {
+		return "", fmt.Errorf("нема release-а за канал %s", channel)
+	}
+	return tag, nil
+}
+
+func (s updateSource) get(rawURL string, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", appName)
+	s.authorize(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("status %d (%s)", resp.StatusCode, rawURL)
+	}
+	return io.ReadAll(resp.Body)
+}
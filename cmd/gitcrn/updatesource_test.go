@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResolveUpdateSource(t *testing.T) {
+	t.Setenv("GITCRN_UPDATE_BASE_URL", "")
+	t.Setenv("GITCRN_TOKEN", "")
+	t.Setenv("GITEA_TOKEN", "")
+
+	gh := resolveUpdateSource(appConfig{ServerURL: defaultServerURL, Token: "secret"})
+	if gh.ReleasesURL != "https://api.github.com/repos/crnobog69/gitcrn-cli-bin/releases" {
+		t.Fatalf("unexpected github releases URL: %s", gh.ReleasesURL)
+	}
+	if gh.DownloadURL != "https://github.com/crnobog69/gitcrn-cli-bin/releases/download" {
+		t.Fatalf("unexpected github download URL: %s", gh.DownloadURL)
+	}
+	if gh.Token != "" {
+		t.Fatalf("gitea token must not be used for github")
+	}
+
+	gt := resolveUpdateSource(appConfig{
+		ServerURL:      "http://100.91.132.35:5000/",
+		Token:          "secret",
+		UpdateProvider: updateProviderGitea,
+		UpdateOwner:    "vltc",
+		UpdateRepo:     "gitcrn",
+	})
+	if gt.ReleasesURL != "http://100.91.132.35:5000/api/v1/repos/vltc/gitcrn/releases" {
+		t.Fatalf("unexpected gitea releases URL: %s", gt.ReleasesURL)
+	}
+	if gt.DownloadURL != "http://100.91.132.35:5000/vltc/gitcrn/releases/download" {
+		t.Fatalf("unexpected gitea download URL: %s", gt.DownloadURL)
+	}
+	if gt.Token != "secret" {
+		t.Fatalf("expected gitea token, got %q", gt.Token)
+	}
+
+	t.Setenv("GITCRN_UPDATE_BASE_URL", "http://127.0.0.1:8080/dl/")
+	if got := resolveUpdateSource(appConfig{UpdateBaseURL: "http://ignored"}).DownloadURL; got != "http://127.0.0.1:8080/dl" {
+		t.Fatalf("env override not applied: %s", got)
+	}
+}
+
+func TestUpdateSourceAuthorizeSameHostOnly(t *testing.T) {
+	src := updateSource{
+		Provider:    updateProviderGitea,
+		ReleasesURL: "http://gitea.local:5000/api/v1/repos/o/r/releases",
+		Token:       "secret",
+	}
+
+	same, _ := http.NewRequest(http.MethodGet, "http://gitea.local:5000/o/r/releases/download/v1.0.0/checksums.txt", nil)
+	src.authorize(same)
+	if got := same.Header.Get("Authorization"); got != "token secret" {
+		t.Fatalf("expected token on gitea host, got %q", got)
+	}
+
+	other, _ := http.NewRequest(http.MethodGet, "http://mirror.local/v1.0.0/checksums.txt", nil)
+	src.authorize(other)
+	if got := other.Header.Get("Authorization"); got != "" {
+		t.Fatalf("token leaked to other host: %q", got)
+	}
+}
+
+func TestFetchLatestReleaseTagGitea(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/vltc/gitcrn/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `[{"tag_name":"v0.9.0-rc.1","prerelease":true},{"tag_name":"v0.8.2"},{"tag_name":"v0.8.10"}]`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GITCRN_TOKEN", "")
+	t.Setenv("GITEA_TOKEN", "")
+	src := resolveUpdateSource(appConfig{
+		ServerURL:      srv.URL,
+		Token:          "secret",
+		UpdateProvider: updateProviderGitea,
+		UpdateOwner:    "vltc",
+		UpdateRepo:     "gitcrn",
+	})
+
+	tag, err := fetchLatestReleaseTag(src, updateChannelStable, 2*time.Second)
+	if err != nil || tag != "v0.8.10" {
+		t.Fatalf("stable: got %q, %v", tag, err)
+	}
+	tag, err = fetchLatestReleaseTag(src, updateChannelPrerelease, 2*time.Second)
+	if err != nil || tag != "v0.9.0-rc.1" {
+		t.Fatalf("prerelease: got %q, %v", tag, err)
+	}
+}